@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"os"
 
 	"github.com/go-i2p/go-rst/pkg/parser"
 	"github.com/go-i2p/go-rst/pkg/renderer"
@@ -18,8 +19,14 @@ func main() {
 	outFileFormat := flag.String("out-format", "html", "Output file format (html, pdf, markdown)")
 	outFile := flag.String("out", "", "Output file path")
 	debug := flag.Bool("debug", false, "Enable debug logging")
+	halt := flag.String("halt", "none", "Diagnostic level that stops the conversion (info, warning, error, severe, none)")
 	flag.Parse()
 
+	haltLevel, err := parser.ParseLevel(*halt)
+	if err != nil {
+		log.Fatalf("Invalid -halt flag: %v", err)
+	}
+
 	if *debug {
 		log.SetFlags(log.Lshortfile | log.LstdFlags)
 	}
@@ -32,11 +39,12 @@ func main() {
 		log.Fatal("Please provide an output HTML file using -out flag")
 	}
 
-	// Read RST content
-	content, err := ioutil.ReadFile(*rstFile)
+	// Open RST content
+	content, err := os.Open(*rstFile)
 	if err != nil {
 		log.Fatalf("Failed to read RST file: %v", err)
 	}
+	defer content.Close()
 
 	if *debug {
 		log.Printf("Loaded RST file: %s", *rstFile)
@@ -58,9 +66,19 @@ func main() {
 
 	// Initialize parser with translator
 	p := parser.NewParser(trans)
+	p.SetHaltLevel(haltLevel)
+	p.SetSourcePath(*rstFile)
 
-	// Parse RST content
-	nodes := p.Parse(string(content))
+	// Parse RST content, reporting any problems found in it
+	nodes, err := p.ParseReader(content)
+	for _, diagnostic := range p.Diagnostics() {
+		if diagnostic.Level >= parser.LevelWarning || *debug {
+			log.Printf("%s:%s", *rstFile, diagnostic)
+		}
+	}
+	if err != nil {
+		log.Fatalf("Failed to parse RST file: %v", err)
+	}
 
 	if *debug {
 		log.Printf("Parsed %d nodes", len(nodes))
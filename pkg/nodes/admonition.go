@@ -0,0 +1,33 @@
+package nodes
+
+import "fmt"
+
+// AdmonitionNode represents an admonition such as a note or a warning. Its
+// children are the body of the admonition.
+type AdmonitionNode struct {
+	*BaseNode
+	kind  string
+	title string
+}
+
+// NewAdmonitionNode creates a new AdmonitionNode of the given kind, such as
+// "note", with the title shown above its body
+func NewAdmonitionNode(kind, title string) *AdmonitionNode {
+	return &AdmonitionNode{
+		BaseNode: NewBaseNode(NodeAdmonition),
+		kind:     kind,
+		title:    title,
+	}
+}
+
+// Kind returns the kind of the admonition, such as "note" or "warning", or
+// "admonition" for a generic admonition with its own title
+func (n *AdmonitionNode) Kind() string { return n.kind }
+
+// Title returns the title of the admonition
+func (n *AdmonitionNode) Title() string { return n.title }
+
+// String representation for debugging
+func (n *AdmonitionNode) String() string {
+	return fmt.Sprintf("Admonition[%s]: %s", n.kind, n.title)
+}
@@ -0,0 +1,96 @@
+package nodes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefinitionListNode represents a list of terms and their definitions
+type DefinitionListNode struct {
+	*BaseNode
+}
+
+// NewDefinitionListNode creates a new, empty DefinitionListNode
+func NewDefinitionListNode() *DefinitionListNode {
+	return &DefinitionListNode{
+		BaseNode: NewBaseNode(NodeDefinitionList),
+	}
+}
+
+// AppendChild adds an item to the definition list
+func (n *DefinitionListNode) AppendChild(item *DefinitionListItemNode) {
+	n.BaseNode.AddChild(item)
+}
+
+// String representation for debugging
+func (n *DefinitionListNode) String() string {
+	return fmt.Sprintf("Definition List with %d items", len(n.Children()))
+}
+
+// TermNode represents the term of a definition list item. Its children are
+// the inline nodes of the term text
+type TermNode struct {
+	*BaseNode
+}
+
+// NewTermNode creates a new TermNode with the given plain text
+func NewTermNode(content string) *TermNode {
+	node := &TermNode{
+		BaseNode: NewBaseNode(NodeTerm),
+	}
+	node.SetContent(content)
+	return node
+}
+
+// String representation for debugging
+func (n *TermNode) String() string {
+	return fmt.Sprintf("Term: %s", n.Content())
+}
+
+// DefinitionListItemNode represents a term with optional classifiers and its
+// definition. The first child is the TermNode; the remaining children are
+// the body elements of the definition
+type DefinitionListItemNode struct {
+	*BaseNode
+	classifiers []string
+}
+
+// NewDefinitionListItemNode creates a new DefinitionListItemNode for term
+func NewDefinitionListItemNode(term *TermNode) *DefinitionListItemNode {
+	node := &DefinitionListItemNode{
+		BaseNode: NewBaseNode(NodeDefinitionListItem),
+	}
+	node.SetContent(term.Content())
+	node.AddChild(term)
+	return node
+}
+
+// Term returns the term being defined
+func (n *DefinitionListItemNode) Term() *TermNode {
+	term, _ := n.Children()[0].(*TermNode)
+	return term
+}
+
+// Classifiers returns the classifiers following the term, such as the type in
+// "name : string"
+func (n *DefinitionListItemNode) Classifiers() []string {
+	return n.classifiers
+}
+
+// AddClassifier adds a classifier to the term
+func (n *DefinitionListItemNode) AddClassifier(classifier string) {
+	n.classifiers = append(n.classifiers, classifier)
+}
+
+// Definition returns the body elements of the definition
+func (n *DefinitionListItemNode) Definition() []Node {
+	return n.Children()[1:]
+}
+
+// String representation for debugging
+func (n *DefinitionListItemNode) String() string {
+	if len(n.classifiers) > 0 {
+		return fmt.Sprintf("Definition: %s : %s", n.Content(), strings.Join(n.classifiers, " : "))
+	}
+	return fmt.Sprintf("Definition: %s", n.Content())
+}
@@ -1,12 +1,17 @@
 package nodes
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 // DirectiveNode represents an RST directive
 type DirectiveNode struct {
 	*BaseNode
 	name       string
 	arguments  []string
+	options    DirectiveOptions
 	rawContent string
 }
 
@@ -16,6 +21,7 @@ func NewDirectiveNode(name string, args []string) *DirectiveNode {
 		BaseNode:   NewBaseNode(NodeDirective),
 		name:       name,
 		arguments:  args,
+		options:    make(DirectiveOptions),
 		rawContent: "",
 	}
 	return node
@@ -27,6 +33,17 @@ func (n *DirectiveNode) Name() string { return n.name }
 // Arguments returns the arguments of the directive
 func (n *DirectiveNode) Arguments() []string { return n.arguments }
 
+// Options returns the options of the directive
+func (n *DirectiveNode) Options() DirectiveOptions { return n.options }
+
+// SetOptions sets the options of the directive
+func (n *DirectiveNode) SetOptions(options DirectiveOptions) {
+	if options == nil {
+		options = make(DirectiveOptions)
+	}
+	n.options = options
+}
+
 // RawContent returns the raw content of the directive
 func (n *DirectiveNode) RawContent() string { return n.rawContent }
 
@@ -39,3 +56,89 @@ func (n *DirectiveNode) SetRawContent(content string) {
 func (n *DirectiveNode) String() string {
 	return fmt.Sprintf("Directive[%s]: %s", n.name, n.Content())
 }
+
+// DirectiveOptions maps the option names of a directive, lowercased, to their
+// values as written in the option field list (":width: 200px"). Flag options
+// such as ":linenos:" have an empty value. The accessors convert values to
+// the type an option expects.
+type DirectiveOptions map[string]string
+
+// Has reports whether the option was given
+func (o DirectiveOptions) Has(name string) bool {
+	_, ok := o[name]
+	return ok
+}
+
+// Value returns the value of the option, or "" if it was not given
+func (o DirectiveOptions) Value(name string) string { return o[name] }
+
+// Int returns the value of the option as an integer, or fallback if it was
+// not given
+func (o DirectiveOptions) Int(name string, fallback int) (int, error) {
+	value, ok := o[name]
+	if !ok {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid option value: (option: %q; value: %q) integer required", name, value)
+	}
+	return n, nil
+}
+
+// List returns the value of the option split at commas and whitespace, as
+// used by options such as "class" and "widths"
+func (o DirectiveOptions) List(name string) []string {
+	return strings.FieldsFunc(o[name], func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+}
+
+// lengthUnits are the units accepted by Length, as in CSS
+var lengthUnits = []string{"em", "ex", "px", "in", "cm", "mm", "pt", "pc", "%", ""}
+
+// Length is a dimension such as "200px" or "50%". An empty unit means
+// pixels.
+type Length struct {
+	Value float64
+	Unit  string
+}
+
+// String returns the length as written in CSS
+func (l Length) String() string {
+	unit := l.Unit
+	if unit == "" {
+		unit = "px"
+	}
+	return strconv.FormatFloat(l.Value, 'f', -1, 64) + unit
+}
+
+// Length returns the value of the option as a length. The boolean is false
+// if the option was not given.
+func (o DirectiveOptions) Length(name string) (Length, bool, error) {
+	value, ok := o[name]
+	if !ok {
+		return Length{}, false, nil
+	}
+	length, err := ParseLength(value)
+	if err != nil {
+		return Length{}, false, fmt.Errorf("invalid option value: (option: %q; value: %q) %v", name, value, err)
+	}
+	return length, true, nil
+}
+
+// ParseLength parses a non-negative length such as "200px", "1.5em" or
+// "50%". A number without a unit is in pixels.
+func ParseLength(text string) (Length, error) {
+	text = strings.TrimSpace(text)
+	for _, unit := range lengthUnits {
+		number, found := strings.CutSuffix(text, unit)
+		if !found {
+			continue
+		}
+		if v, err := strconv.ParseFloat(strings.TrimSpace(number), 64); err == nil && v >= 0 {
+			return Length{Value: v, Unit: unit}, nil
+		}
+	}
+	return Length{}, fmt.Errorf("valid length unit required")
+}
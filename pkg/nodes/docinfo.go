@@ -0,0 +1,96 @@
+package nodes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DocInfoNode represents the bibliographic fields of a document, taken from a
+// field list at its start. The children are the original fields; the
+// recognized bibliographic fields are also available through typed accessors
+type DocInfoNode struct {
+	*BaseNode
+	authors   []string
+	date      string
+	version   string
+	status    string
+	copyright string
+	abstract  []Node
+}
+
+// NewDocInfoNode creates a new, empty DocInfoNode
+func NewDocInfoNode() *DocInfoNode {
+	return &DocInfoNode{
+		BaseNode: NewBaseNode(NodeDocInfo),
+	}
+}
+
+// Author returns the document authors separated by commas
+func (n *DocInfoNode) Author() string {
+	return strings.Join(n.authors, ", ")
+}
+
+// Authors returns the document authors
+func (n *DocInfoNode) Authors() []string {
+	return n.authors
+}
+
+// AddAuthor adds a document author
+func (n *DocInfoNode) AddAuthor(author string) {
+	n.authors = append(n.authors, author)
+}
+
+// Date returns the document date as written in the source
+func (n *DocInfoNode) Date() string {
+	return n.date
+}
+
+// SetDate sets the document date
+func (n *DocInfoNode) SetDate(date string) {
+	n.date = date
+}
+
+// Version returns the document version
+func (n *DocInfoNode) Version() string {
+	return n.version
+}
+
+// SetVersion sets the document version
+func (n *DocInfoNode) SetVersion(version string) {
+	n.version = version
+}
+
+// Status returns the document status, such as "draft"
+func (n *DocInfoNode) Status() string {
+	return n.status
+}
+
+// SetStatus sets the document status
+func (n *DocInfoNode) SetStatus(status string) {
+	n.status = status
+}
+
+// Copyright returns the document copyright statement
+func (n *DocInfoNode) Copyright() string {
+	return n.copyright
+}
+
+// SetCopyright sets the document copyright statement
+func (n *DocInfoNode) SetCopyright(copyright string) {
+	n.copyright = copyright
+}
+
+// Abstract returns the body elements of the document abstract
+func (n *DocInfoNode) Abstract() []Node {
+	return n.abstract
+}
+
+// SetAbstract sets the body elements of the document abstract
+func (n *DocInfoNode) SetAbstract(abstract []Node) {
+	n.abstract = abstract
+}
+
+// String representation for debugging
+func (n *DocInfoNode) String() string {
+	return fmt.Sprintf("DocInfo: %s %s", n.Author(), n.date)
+}
@@ -1,10 +1,40 @@
 package nodes
 
+import "strings"
+
+// DoctestExample is one interactive Python statement of a doctest block: the
+// command after the ">>> " prompt, the lines after "... " prompts that
+// continue it, and the output expected from running it.
+type DoctestExample struct {
+	Command      string
+	Continuation []string
+	Expected     string
+}
+
+// Source returns the command and its continuation lines without prompts.
+func (e DoctestExample) Source() string {
+	return strings.Join(append([]string{e.Command}, e.Continuation...), "\n")
+}
+
+// Lines returns the example as it appears in a session, with the ">>> " and
+// "... " prompts restored and followed by the expected output.
+func (e DoctestExample) Lines() []string {
+	lines := []string{strings.TrimRight(">>> "+e.Command, " ")}
+	for _, line := range e.Continuation {
+		lines = append(lines, strings.TrimRight("... "+line, " "))
+	}
+	if e.Expected != "" {
+		lines = append(lines, strings.Split(e.Expected, "\n")...)
+	}
+	return lines
+}
+
 // DoctestNode represents a Python doctest block with code and expected output.
 type DoctestNode struct {
 	BaseNode
 	code           string
 	expectedOutput string
+	examples       []DoctestExample
 }
 
 func (n *DoctestNode) Expected() string {
@@ -26,6 +56,35 @@ func NewDoctestNode() *DoctestNode {
 	}
 }
 
+// AddExample appends an example to the doctest. The first example also sets
+// the code and expected output of the node.
+func (n *DoctestNode) AddExample(example DoctestExample) {
+	if len(n.examples) == 0 {
+		n.code = example.Source()
+		n.expectedOutput = example.Expected
+	}
+	n.examples = append(n.examples, example)
+}
+
+// Examples returns the examples of the doctest in order. A node built with
+// SetCode and SetExpectedOutput alone has a single example.
+func (n *DoctestNode) Examples() []DoctestExample {
+	if len(n.examples) == 0 && n.code != "" {
+		lines := strings.Split(n.code, "\n")
+		return []DoctestExample{{Command: lines[0], Continuation: lines[1:], Expected: n.expectedOutput}}
+	}
+	return n.examples
+}
+
+// Text returns the doctest block as written, prompts included.
+func (n *DoctestNode) Text() string {
+	lines := make([]string, 0)
+	for _, example := range n.Examples() {
+		lines = append(lines, example.Lines()...)
+	}
+	return strings.Join(lines, "\n")
+}
+
 // SetCode sets the code content of the doctest.
 func (n *DoctestNode) SetCode(code string) {
 	n.code = code
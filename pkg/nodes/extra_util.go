@@ -1,6 +1,9 @@
 package nodes
 
-import "strings"
+import (
+	"slices"
+	"strings"
+)
 
 // GetIndentedContent Utility function to get node content with proper indentation
 func GetIndentedContent(node Node) string {
@@ -15,3 +18,65 @@ func GetIndentedContent(node Node) string {
 	}
 	return content
 }
+
+// TextContent returns the plain text of a node. For nodes with inline children
+// the text of the children is concatenated, dropping any markup.
+func TextContent(node Node) string {
+	children := node.Children()
+	if len(children) == 0 {
+		return node.Content()
+	}
+	var b strings.Builder
+	for _, child := range children {
+		b.WriteString(TextContent(child))
+	}
+	return b.String()
+}
+
+// CopyInline returns a deep copy of an inline node: a node of the same type
+// with its own attributes, position, ID and copies of its children. Nodes of
+// other types are returned unchanged.
+func CopyInline(node Node) Node {
+	switch n := node.(type) {
+	case *TextNode:
+		return &TextNode{copyBase(n.BaseNode)}
+	case *EmphasisNode:
+		return &EmphasisNode{copyBase(n.BaseNode)}
+	case *StrongNode:
+		return &StrongNode{copyBase(n.BaseNode)}
+	case *LiteralNode:
+		return &LiteralNode{copyBase(n.BaseNode)}
+	case *InterpretedTextNode:
+		return &InterpretedTextNode{BaseNode: copyBase(n.BaseNode), role: n.role}
+	case *InlineNode:
+		clone := *n
+		clone.BaseNode = copyBase(n.BaseNode)
+		clone.classes = slices.Clone(n.classes)
+		return &clone
+	case *LinkNode:
+		clone := *n
+		clone.BaseNode = copyBase(n.BaseNode)
+		return &clone
+	case *SubstitutionReferenceNode:
+		return &SubstitutionReferenceNode{copyBase(n.BaseNode)}
+	case *FootnoteReferenceNode:
+		clone := *n
+		clone.BaseNode = copyBase(n.BaseNode)
+		return &clone
+	case *ImageNode:
+		clone := *n
+		clone.BaseNode = copyBase(n.BaseNode)
+		return &clone
+	}
+	return node
+}
+
+// copyBase returns a copy of base with copies of its children.
+func copyBase(base *BaseNode) *BaseNode {
+	clone := *base
+	clone.children = make([]Node, len(base.children))
+	for i, child := range base.children {
+		clone.children[i] = CopyInline(child)
+	}
+	return &clone
+}
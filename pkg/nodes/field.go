@@ -0,0 +1,51 @@
+package nodes
+
+import "fmt"
+
+// FieldListNode represents a list of fields, such as ":Author: Jane Doe"
+type FieldListNode struct {
+	*BaseNode
+}
+
+// NewFieldListNode creates a new, empty FieldListNode
+func NewFieldListNode() *FieldListNode {
+	return &FieldListNode{
+		BaseNode: NewBaseNode(NodeFieldList),
+	}
+}
+
+// AppendChild adds a field to the field list
+func (n *FieldListNode) AppendChild(field *FieldNode) {
+	n.BaseNode.AddChild(field)
+}
+
+// String representation for debugging
+func (n *FieldListNode) String() string {
+	return fmt.Sprintf("Field List with %d fields", len(n.Children()))
+}
+
+// FieldNode represents a single field: a name and a body whose elements are
+// the children of the node
+type FieldNode struct {
+	*BaseNode
+	name string
+}
+
+// NewFieldNode creates a new FieldNode with the given name
+func NewFieldNode(name string) *FieldNode {
+	node := &FieldNode{
+		BaseNode: NewBaseNode(NodeField),
+		name:     name,
+	}
+	return node
+}
+
+// Name returns the field name
+func (n *FieldNode) Name() string {
+	return n.name
+}
+
+// String representation for debugging
+func (n *FieldNode) String() string {
+	return fmt.Sprintf("Field: %s", n.name)
+}
@@ -0,0 +1,101 @@
+package nodes
+
+import "fmt"
+
+// FootnoteKind tells how a footnote is labeled and matched with its
+// references
+type FootnoteKind int
+
+const (
+	FootnoteManual   FootnoteKind = iota // FootnoteManual is numbered in the source, as in [1]
+	FootnoteNumbered                     // FootnoteNumbered is numbered automatically, as in [#] or [#label]
+	FootnoteSymbol                       // FootnoteSymbol is given the next footnote symbol, as in [*]
+	FootnoteCitation                     // FootnoteCitation is a citation, as in [CIT2002]
+)
+
+// footnoteLabel holds what footnotes and their references have in common
+type footnoteLabel struct {
+	kind  FootnoteKind
+	name  string
+	label string
+}
+
+// Kind returns how the footnote is labeled
+func (f *footnoteLabel) Kind() FootnoteKind { return f.kind }
+
+// IsCitation returns true for citations and citation references
+func (f *footnoteLabel) IsCitation() bool { return f.kind == FootnoteCitation }
+
+// Name returns the normalized name that references match, or "" for an
+// anonymous auto-numbered or symbol footnote, which is matched in order
+func (f *footnoteLabel) Name() string { return f.name }
+
+// Label returns the label shown for the footnote: its number, symbol or
+// citation label
+func (f *footnoteLabel) Label() string { return f.label }
+
+// SetLabel sets the label shown for the footnote
+func (f *footnoteLabel) SetLabel(label string) { f.label = label }
+
+// FootnoteNode represents a footnote or citation (".. [1] Text") with its
+// body as children
+type FootnoteNode struct {
+	*BaseNode
+	footnoteLabel
+	backrefs []string
+}
+
+// NewFootnoteNode creates a new FootnoteNode of the given kind. The label is
+// the label as written, which auto-numbered and symbol footnotes replace
+// when references are resolved.
+func NewFootnoteNode(kind FootnoteKind, name, label string) *FootnoteNode {
+	return &FootnoteNode{
+		BaseNode:      NewBaseNode(NodeFootnote),
+		footnoteLabel: footnoteLabel{kind: kind, name: name, label: label},
+	}
+}
+
+// Backrefs returns the IDs of the references to the footnote, in document
+// order
+func (n *FootnoteNode) Backrefs() []string { return n.backrefs }
+
+// AddBackref records a reference to the footnote
+func (n *FootnoteNode) AddBackref(id string) {
+	n.backrefs = append(n.backrefs, id)
+}
+
+// String representation for debugging
+func (n *FootnoteNode) String() string {
+	return fmt.Sprintf("Footnote[%s]", n.label)
+}
+
+// FootnoteReferenceNode represents a reference to a footnote or citation
+// ("[1]_")
+type FootnoteReferenceNode struct {
+	*BaseNode
+	footnoteLabel
+	refID string
+}
+
+// NewFootnoteReferenceNode creates a new FootnoteReferenceNode of the given
+// kind, with the label as written
+func NewFootnoteReferenceNode(kind FootnoteKind, name, label string) *FootnoteReferenceNode {
+	node := &FootnoteReferenceNode{
+		BaseNode:      NewBaseNode(NodeFootnoteReference),
+		footnoteLabel: footnoteLabel{kind: kind, name: name, label: label},
+	}
+	node.SetContent(label)
+	return node
+}
+
+// RefID returns the ID of the referenced footnote, or "" if the reference is
+// unresolved
+func (n *FootnoteReferenceNode) RefID() string { return n.refID }
+
+// SetRefID sets the ID of the referenced footnote
+func (n *FootnoteReferenceNode) SetRefID(id string) { n.refID = id }
+
+// String representation for debugging
+func (n *FootnoteReferenceNode) String() string {
+	return fmt.Sprintf("FootnoteReference[%s]", n.label)
+}
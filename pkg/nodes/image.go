@@ -0,0 +1,64 @@
+package nodes
+
+import "fmt"
+
+// ImageNode represents an image, as created by the image and figure
+// directives. Its content is the alternate text.
+type ImageNode struct {
+	*BaseNode
+	uri    string
+	width  string
+	height string
+	align  string
+	target string
+}
+
+// NewImageNode creates a new ImageNode for the image at uri
+func NewImageNode(uri, alt string) *ImageNode {
+	node := &ImageNode{
+		BaseNode: NewBaseNode(NodeImage),
+		uri:      uri,
+	}
+	node.SetContent(alt)
+	return node
+}
+
+// URI returns the location of the image
+func (n *ImageNode) URI() string { return n.uri }
+
+// Alt returns the alternate text of the image
+func (n *ImageNode) Alt() string { return n.Content() }
+
+// Width returns the width of the image as a CSS length, or ""
+func (n *ImageNode) Width() string { return n.width }
+
+// Height returns the height of the image as a CSS length, or ""
+func (n *ImageNode) Height() string { return n.height }
+
+// SetSize sets the width and height of the image as CSS lengths. Either may
+// be empty.
+func (n *ImageNode) SetSize(width, height string) {
+	n.width = width
+	n.height = height
+}
+
+// Align returns the alignment of the image, such as "left" or "center"
+func (n *ImageNode) Align() string { return n.align }
+
+// SetAlign sets the alignment of the image
+func (n *ImageNode) SetAlign(align string) {
+	n.align = align
+}
+
+// Target returns the URI the image links to, or ""
+func (n *ImageNode) Target() string { return n.target }
+
+// SetTarget sets the URI the image links to
+func (n *ImageNode) SetTarget(target string) {
+	n.target = target
+}
+
+// String representation for debugging
+func (n *ImageNode) String() string {
+	return fmt.Sprintf("Image: %s", n.uri)
+}
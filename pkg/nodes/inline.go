@@ -0,0 +1,82 @@
+package nodes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InlineKind is the kind of text an InlineNode holds
+type InlineKind string
+
+const (
+	InlineSpan         InlineKind = "inline"       // text marked with classes by a custom role
+	InlineSubscript    InlineKind = "subscript"    // subscript text
+	InlineSuperscript  InlineKind = "superscript"  // superscript text
+	InlineAbbreviation InlineKind = "abbreviation" // an abbreviation
+	InlineCode         InlineKind = "code"         // inline code, with an optional language
+	InlineRaw          InlineKind = "raw"          // text passed through to writers of a format
+)
+
+// InlineNode represents inline text produced by an interpreted text role. It
+// holds either its text as content or, when it wraps the nodes of another
+// role to give them classes, those nodes as children.
+type InlineNode struct {
+	*BaseNode
+	kind     InlineKind
+	classes  []string
+	language string
+	format   string
+}
+
+// NewInlineNode creates a new InlineNode of the given kind and content
+func NewInlineNode(kind InlineKind, content string) *InlineNode {
+	node := &InlineNode{
+		BaseNode: NewBaseNode(NodeInline),
+		kind:     kind,
+	}
+	node.SetContent(content)
+	return node
+}
+
+// Kind returns the kind of the inline text
+func (n *InlineNode) Kind() InlineKind { return n.kind }
+
+// Classes returns the classes of the inline text
+func (n *InlineNode) Classes() []string { return n.classes }
+
+// SetClasses sets the classes of the inline text
+func (n *InlineNode) SetClasses(classes []string) {
+	n.classes = classes
+}
+
+// Language returns the language of inline code
+func (n *InlineNode) Language() string { return n.language }
+
+// SetLanguage sets the language of inline code
+func (n *InlineNode) SetLanguage(language string) {
+	n.language = language
+}
+
+// Format returns the space-separated output formats of raw text, such as
+// "html"
+func (n *InlineNode) Format() string { return n.format }
+
+// SetFormat sets the output formats of raw text
+func (n *InlineNode) SetFormat(format string) {
+	n.format = format
+}
+
+// HasFormat reports whether raw text is meant for the given output format
+func (n *InlineNode) HasFormat(format string) bool {
+	for _, f := range strings.Fields(n.format) {
+		if strings.EqualFold(f, format) {
+			return true
+		}
+	}
+	return false
+}
+
+// String representation for debugging
+func (n *InlineNode) String() string {
+	return fmt.Sprintf("Inline[%s]: %s", n.kind, n.Content())
+}
@@ -0,0 +1,28 @@
+package nodes
+
+import "fmt"
+
+// InterpretedTextNode represents interpreted text (`text` or :role:`text`)
+type InterpretedTextNode struct {
+	*BaseNode
+	role string
+}
+
+// NewInterpretedTextNode creates a new InterpretedTextNode with the given role
+// and content. An empty role selects the default role.
+func NewInterpretedTextNode(role, content string) *InterpretedTextNode {
+	node := &InterpretedTextNode{
+		BaseNode: NewBaseNode(NodeInterpretedText),
+		role:     role,
+	}
+	node.SetContent(content)
+	return node
+}
+
+// Role returns the role of the interpreted text, or "" for the default role
+func (n *InterpretedTextNode) Role() string { return n.role }
+
+// String representation for debugging
+func (n *InterpretedTextNode) String() string {
+	return fmt.Sprintf("Interpreted[%s]: %s", n.role, n.Content())
+}
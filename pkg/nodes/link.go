@@ -5,8 +5,11 @@ import "fmt"
 // LinkNode represents a hyperlink
 type LinkNode struct {
 	*BaseNode
-	url   string
-	title string
+	url       string
+	title     string
+	refName   string
+	refID     string
+	anonymous bool
 }
 
 // NewLinkNode creates a new LinkNode with the given text, URL, and title
@@ -23,9 +26,33 @@ func NewLinkNode(text, url, title string) *LinkNode {
 // URL returns the URL of the link
 func (n *LinkNode) URL() string { return n.url }
 
+// SetURL sets the URL of the link
+func (n *LinkNode) SetURL(url string) { n.url = url }
+
 // Title returns the URL of the link
 func (n *LinkNode) Title() string { return n.title }
 
+// RefName returns the normalized name of the target the link refers to, or ""
+// if the link was written with its URL. A named reference with an embedded
+// URI has both, and defines a target of that name.
+func (n *LinkNode) RefName() string { return n.refName }
+
+// SetRefName sets the name of the target the link refers to
+func (n *LinkNode) SetRefName(name string) { n.refName = name }
+
+// RefID returns the ID of the element in the document the link points to,
+// or "" for a link to a URI or an unresolved reference
+func (n *LinkNode) RefID() string { return n.refID }
+
+// SetRefID sets the ID of the element the link points to
+func (n *LinkNode) SetRefID(id string) { n.refID = id }
+
+// IsAnonymous returns true for anonymous references (`text`__)
+func (n *LinkNode) IsAnonymous() bool { return n.anonymous }
+
+// SetAnonymous marks the link as an anonymous reference
+func (n *LinkNode) SetAnonymous(anonymous bool) { n.anonymous = anonymous }
+
 // String representation for debugging
 func (n *LinkNode) String() string {
 	return fmt.Sprintf("Link[%s](%s)", n.Content(), n.url)
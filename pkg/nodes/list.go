@@ -1,12 +1,32 @@
 package nodes
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EnumType is the numbering sequence of an enumerated list
+type EnumType string
+
+const (
+	EnumArabic     EnumType = "arabic"     // 1, 2, 3
+	EnumLowerAlpha EnumType = "loweralpha" // a, b, c
+	EnumUpperAlpha EnumType = "upperalpha" // A, B, C
+	EnumLowerRoman EnumType = "lowerroman" // i, ii, iii
+	EnumUpperRoman EnumType = "upperroman" // I, II, III
+)
 
 // ListNode represents an ordered or unordered list
 type ListNode struct {
 	*BaseNode
-	ordered bool
-	indent  int
+	ordered    bool
+	indent     int
+	bullet     string
+	enumType   EnumType
+	startValue int
+	prefix     string
+	suffix     string
 }
 
 func (n *ListNode) Indent() int {
@@ -22,11 +42,19 @@ func (n *ListNode) AppendChild(listItem *ListItemNode) {
 	n.BaseNode.AddChild(listItem)
 }
 
-// NewListNode creates a new ListNode with the given ordered flag
+// NewListNode creates a new ListNode with the given ordered flag. Ordered
+// lists default to arabic numbering starting at 1 with a period suffix,
+// unordered lists to a "*" bullet.
 func NewListNode(ordered bool) *ListNode {
 	node := &ListNode{
 		BaseNode: NewBaseNode(NodeList),
 		ordered:  ordered,
+		bullet:   "*",
+	}
+	if ordered {
+		node.enumType = EnumArabic
+		node.startValue = 1
+		node.suffix = "."
 	}
 	return node
 }
@@ -36,6 +64,107 @@ func (n *ListNode) IsOrdered() bool {
 	return n.ordered
 }
 
+// Bullet returns the bullet character of an unordered list
+func (n *ListNode) Bullet() string {
+	return n.bullet
+}
+
+// SetBullet sets the bullet character of an unordered list
+func (n *ListNode) SetBullet(bullet string) {
+	n.bullet = bullet
+}
+
+// EnumType returns the numbering sequence of an ordered list
+func (n *ListNode) EnumType() EnumType {
+	return n.enumType
+}
+
+// SetEnumType sets the numbering sequence of an ordered list
+func (n *ListNode) SetEnumType(enumType EnumType) {
+	n.enumType = enumType
+}
+
+// StartValue returns the ordinal of the first item of an ordered list
+func (n *ListNode) StartValue() int {
+	return n.startValue
+}
+
+// SetStartValue sets the ordinal of the first item of an ordered list
+func (n *ListNode) SetStartValue(start int) {
+	n.startValue = start
+}
+
+// Prefix returns the text before each enumerator, such as "(" in "(a)"
+func (n *ListNode) Prefix() string {
+	return n.prefix
+}
+
+// SetPrefix sets the text before each enumerator
+func (n *ListNode) SetPrefix(prefix string) {
+	n.prefix = prefix
+}
+
+// Suffix returns the text after each enumerator, "." or ")"
+func (n *ListNode) Suffix() string {
+	return n.suffix
+}
+
+// SetSuffix sets the text after each enumerator
+func (n *ListNode) SetSuffix(suffix string) {
+	n.suffix = suffix
+}
+
+// Marker returns the marker of the item at index i: the bullet of an
+// unordered list, or the formatted enumerator of an ordered one, such as
+// "(c)" or "iv."
+func (n *ListNode) Marker(i int) string {
+	if !n.ordered {
+		return n.bullet
+	}
+	return n.prefix + FormatOrdinal(n.startValue+i, n.enumType) + n.suffix
+}
+
+// FormatOrdinal formats an ordinal in the given numbering sequence. Alphabetic
+// sequences only reach 26 and roman numerals 4999; larger ordinals are
+// formatted in arabic.
+func FormatOrdinal(ordinal int, enumType EnumType) string {
+	switch enumType {
+	case EnumLowerAlpha, EnumUpperAlpha:
+		if ordinal < 1 || ordinal > 26 {
+			break
+		}
+		letter := string(rune('a' + ordinal - 1))
+		if enumType == EnumUpperAlpha {
+			letter = strings.ToUpper(letter)
+		}
+		return letter
+	case EnumLowerRoman, EnumUpperRoman:
+		if ordinal < 1 || ordinal > 4999 {
+			break
+		}
+		roman := toRoman(ordinal)
+		if enumType == EnumLowerRoman {
+			roman = strings.ToLower(roman)
+		}
+		return roman
+	}
+	return strconv.Itoa(ordinal)
+}
+
+// toRoman converts a positive integer to upper-case roman numerals
+func toRoman(n int) string {
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	symbols := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
+	var b strings.Builder
+	for i, value := range values {
+		for n >= value {
+			b.WriteString(symbols[i])
+			n -= value
+		}
+	}
+	return b.String()
+}
+
 // ListItemNode represents an individual list item
 type ListItemNode struct {
 	*BaseNode
@@ -0,0 +1,22 @@
+package nodes
+
+import "fmt"
+
+// LiteralNode represents inline literal text, written between double backquotes
+type LiteralNode struct {
+	*BaseNode
+}
+
+// NewLiteralNode creates a new LiteralNode with the given content
+func NewLiteralNode(content string) *LiteralNode {
+	node := &LiteralNode{
+		BaseNode: NewBaseNode(NodeLiteral),
+	}
+	node.SetContent(content)
+	return node
+}
+
+// String representation for debugging
+func (n *LiteralNode) String() string {
+	return fmt.Sprintf("Literal: %s", n.Content())
+}
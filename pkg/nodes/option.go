@@ -0,0 +1,81 @@
+package nodes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OptionListNode represents a list of command-line options and their
+// descriptions
+type OptionListNode struct {
+	*BaseNode
+}
+
+// NewOptionListNode creates a new, empty OptionListNode
+func NewOptionListNode() *OptionListNode {
+	return &OptionListNode{
+		BaseNode: NewBaseNode(NodeOptionList),
+	}
+}
+
+// AppendChild adds an item to the option list
+func (n *OptionListNode) AppendChild(item *OptionListItemNode) {
+	n.BaseNode.AddChild(item)
+}
+
+// String representation for debugging
+func (n *OptionListNode) String() string {
+	return fmt.Sprintf("Option List with %d items", len(n.Children()))
+}
+
+// Option is a single command-line option, such as "-o FILE" or "--output=FILE"
+type Option struct {
+	Name      string // option string including its prefix, such as "--output"
+	Delimiter string // text between the name and the argument: "", " " or "="
+	Argument  string // argument placeholder, such as "FILE" or "<path>"
+}
+
+// String returns the option as written in the source
+func (o Option) String() string {
+	return o.Name + o.Delimiter + o.Argument
+}
+
+// OptionListItemNode represents a group of synonymous options and their
+// description. The children are the body elements of the description
+type OptionListItemNode struct {
+	*BaseNode
+	options []Option
+}
+
+// NewOptionListItemNode creates a new OptionListItemNode for an option group
+func NewOptionListItemNode(options []Option) *OptionListItemNode {
+	return &OptionListItemNode{
+		BaseNode: NewBaseNode(NodeOptionListItem),
+		options:  options,
+	}
+}
+
+// Options returns the options of the group
+func (n *OptionListItemNode) Options() []Option {
+	return n.options
+}
+
+// OptionGroup returns the options of the group separated by commas, as in
+// "-v, --verbose"
+func (n *OptionListItemNode) OptionGroup() string {
+	group := make([]string, len(n.options))
+	for i, option := range n.options {
+		group[i] = option.String()
+	}
+	return strings.Join(group, ", ")
+}
+
+// Description returns the body elements of the option description
+func (n *OptionListItemNode) Description() []Node {
+	return n.Children()
+}
+
+// String representation for debugging
+func (n *OptionListItemNode) String() string {
+	return fmt.Sprintf("Option: %s", n.OptionGroup())
+}
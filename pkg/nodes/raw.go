@@ -0,0 +1,42 @@
+package nodes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RawNode represents content passed through untouched to writers of the
+// given output formats (".. raw:: html"), with the content as its content
+type RawNode struct {
+	*BaseNode
+	format string
+}
+
+// NewRawNode creates a new RawNode for the space-separated output formats
+func NewRawNode(format, content string) *RawNode {
+	node := &RawNode{
+		BaseNode: NewBaseNode(NodeRaw),
+		format:   format,
+	}
+	node.SetContent(content)
+	return node
+}
+
+// Format returns the space-separated output formats of the content, such as
+// "html"
+func (n *RawNode) Format() string { return n.format }
+
+// HasFormat reports whether the content is meant for the given output format
+func (n *RawNode) HasFormat(format string) bool {
+	for _, f := range strings.Fields(n.format) {
+		if strings.EqualFold(f, format) {
+			return true
+		}
+	}
+	return false
+}
+
+// String representation for debugging
+func (n *RawNode) String() string {
+	return fmt.Sprintf("Raw[%s]: %d bytes", n.format, len(n.Content()))
+}
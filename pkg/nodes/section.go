@@ -0,0 +1,43 @@
+package nodes
+
+import "fmt"
+
+// SectionNode represents a section: its heading followed by the body elements
+// and subsections up to the next heading of the same or a higher level
+type SectionNode struct {
+	*BaseNode
+}
+
+// NewSectionNode creates a new SectionNode at the given level
+func NewSectionNode(level int) *SectionNode {
+	node := &SectionNode{
+		BaseNode: NewBaseNode(NodeSection),
+	}
+	node.SetLevel(level)
+	return node
+}
+
+// Heading returns the section heading, which is always the first child
+func (n *SectionNode) Heading() *HeadingNode {
+	if len(n.Children()) == 0 {
+		return nil
+	}
+	heading, _ := n.Children()[0].(*HeadingNode)
+	return heading
+}
+
+// Body returns the children of the section that follow its heading
+func (n *SectionNode) Body() []Node {
+	if n.Heading() == nil {
+		return n.Children()
+	}
+	return n.Children()[1:]
+}
+
+// String representation for debugging
+func (n *SectionNode) String() string {
+	if heading := n.Heading(); heading != nil {
+		return fmt.Sprintf("Section[%d]: %s", n.Level(), heading.Content())
+	}
+	return fmt.Sprintf("Section[%d]", n.Level())
+}
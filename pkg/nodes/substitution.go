@@ -0,0 +1,26 @@
+package nodes
+
+import "fmt"
+
+// SubstitutionReferenceNode represents a substitution reference (|name|)
+type SubstitutionReferenceNode struct {
+	*BaseNode
+}
+
+// NewSubstitutionReferenceNode creates a new SubstitutionReferenceNode for the
+// given substitution name
+func NewSubstitutionReferenceNode(name string) *SubstitutionReferenceNode {
+	node := &SubstitutionReferenceNode{
+		BaseNode: NewBaseNode(NodeSubstitutionReference),
+	}
+	node.SetContent(name)
+	return node
+}
+
+// Name returns the referenced substitution name
+func (n *SubstitutionReferenceNode) Name() string { return n.Content() }
+
+// String representation for debugging
+func (n *SubstitutionReferenceNode) String() string {
+	return fmt.Sprintf("SubstitutionReference: |%s|", n.Content())
+}
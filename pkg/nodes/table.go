@@ -2,39 +2,227 @@ package nodes
 
 import "fmt"
 
-// TableNode represents a table structure
+// TableNode represents a table structure. Its children are the table rows,
+// header rows first
 type TableNode struct {
 	*BaseNode
-	headers []string
-	rows    [][]string
+	title        string
+	headerRows   int
+	stubColumns  int
+	columnWidths []int
+	align        string
 }
 
 // NewTableNode creates a new TableNode
 func NewTableNode() *TableNode {
 	return &TableNode{
 		BaseNode: NewBaseNode(NodeTable),
-		headers:  make([]string, 0),
-		rows:     make([][]string, 0),
 	}
 }
 
-// SetHeaders sets the headers of the table
+// Title returns the title of the table, if any
+func (n *TableNode) Title() string { return n.title }
+
+// SetTitle sets the title of the table
+func (n *TableNode) SetTitle(title string) {
+	n.title = title
+}
+
+// StubColumns returns the number of leading columns that hold row titles
+func (n *TableNode) StubColumns() int { return n.stubColumns }
+
+// SetStubColumns sets the number of leading columns that hold row titles
+func (n *TableNode) SetStubColumns(columns int) {
+	n.stubColumns = columns
+}
+
+// Align returns the horizontal alignment of the table: "left", "center",
+// "right", or empty for the default
+func (n *TableNode) Align() string { return n.align }
+
+// SetAlign sets the horizontal alignment of the table
+func (n *TableNode) SetAlign(align string) {
+	n.align = align
+}
+
+// AddHeaderRow adds a row to the table header, after any existing header rows
+func (n *TableNode) AddHeaderRow(row *TableRowNode) {
+	n.children = append(n.children, nil)
+	copy(n.children[n.headerRows+1:], n.children[n.headerRows:])
+	n.children[n.headerRows] = row
+	n.headerRows++
+}
+
+// AddBodyRow adds a row to the table body
+func (n *TableNode) AddBodyRow(row *TableRowNode) {
+	n.children = append(n.children, row)
+}
+
+// HeaderRows returns the rows of the table header
+func (n *TableNode) HeaderRows() []*TableRowNode {
+	return tableRows(n.children[:n.headerRows])
+}
+
+// BodyRows returns the rows of the table body
+func (n *TableNode) BodyRows() []*TableRowNode {
+	return tableRows(n.children[n.headerRows:])
+}
+
+// ColumnWidths returns the relative widths of the columns, if known
+func (n *TableNode) ColumnWidths() []int { return n.columnWidths }
+
+// SetColumnWidths sets the relative widths of the columns
+func (n *TableNode) SetColumnWidths(widths []int) {
+	n.columnWidths = widths
+}
+
+// Columns returns the number of columns of the table
+func (n *TableNode) Columns() int {
+	if len(n.columnWidths) > 0 {
+		return len(n.columnWidths)
+	}
+	columns := 0
+	for _, row := range tableRows(n.children) {
+		width := 0
+		for _, cell := range row.Cells() {
+			width += cell.ColSpan()
+		}
+		columns = max(columns, width)
+	}
+	return columns
+}
+
+// SetHeaders replaces the table header with a single row of text cells
 func (n *TableNode) SetHeaders(headers []string) {
-	n.headers = headers
+	n.children = n.children[n.headerRows:]
+	n.headerRows = 0
+	n.AddHeaderRow(NewTextRow(headers))
 }
 
-// AddRow adds a row to the table
+// AddRow adds a row of text cells to the table body
 func (n *TableNode) AddRow(row []string) {
-	n.rows = append(n.rows, row)
+	n.AddBodyRow(NewTextRow(row))
 }
 
-// Headers returns the headers of the table
-func (n *TableNode) Headers() []string { return n.headers }
+// Headers returns the text of the cells of the first header row
+func (n *TableNode) Headers() []string {
+	if n.headerRows == 0 {
+		return []string{}
+	}
+	return n.HeaderRows()[0].Texts()
+}
 
-// Rows returns the rows of the table
-func (n *TableNode) Rows() [][]string { return n.rows }
+// Rows returns the text of the cells of the body rows
+func (n *TableNode) Rows() [][]string {
+	rows := make([][]string, 0)
+	for _, row := range n.BodyRows() {
+		rows = append(rows, row.Texts())
+	}
+	return rows
+}
 
 // String representation for debugging
 func (n *TableNode) String() string {
-	return fmt.Sprintf("Table: %d columns x %d rows", len(n.headers), len(n.rows))
+	return fmt.Sprintf("Table: %d columns x %d rows (%d header)", n.Columns(), len(n.children), n.headerRows)
+}
+
+// tableRows returns the rows among children
+func tableRows(children []Node) []*TableRowNode {
+	rows := make([]*TableRowNode, 0, len(children))
+	for _, child := range children {
+		if row, ok := child.(*TableRowNode); ok {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// TableRowNode represents a row of a table. Its children are the cells that
+// start in the row; cells spanning into it from rows above are not repeated
+type TableRowNode struct {
+	*BaseNode
+}
+
+// NewTableRowNode creates a new, empty TableRowNode
+func NewTableRowNode() *TableRowNode {
+	return &TableRowNode{
+		BaseNode: NewBaseNode(NodeTableRow),
+	}
+}
+
+// NewTextRow creates a row with a plain text cell for each of texts
+func NewTextRow(texts []string) *TableRowNode {
+	row := NewTableRowNode()
+	for _, text := range texts {
+		cell := NewTableCellNode()
+		cell.SetContent(text)
+		row.AppendCell(cell)
+	}
+	return row
+}
+
+// AppendCell adds a cell to the row
+func (n *TableRowNode) AppendCell(cell *TableCellNode) {
+	n.BaseNode.AddChild(cell)
+}
+
+// Cells returns the cells that start in the row
+func (n *TableRowNode) Cells() []*TableCellNode {
+	cells := make([]*TableCellNode, 0, len(n.children))
+	for _, child := range n.children {
+		if cell, ok := child.(*TableCellNode); ok {
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
+
+// Texts returns the text of each cell of the row
+func (n *TableRowNode) Texts() []string {
+	texts := make([]string, 0, len(n.children))
+	for _, cell := range n.Cells() {
+		texts = append(texts, TextContent(cell))
+	}
+	return texts
+}
+
+// String representation for debugging
+func (n *TableRowNode) String() string {
+	return fmt.Sprintf("Table Row with %d cells", len(n.children))
+}
+
+// TableCellNode represents a table cell. Its children are the body elements
+// of the cell; a cell created from plain text has the text as content
+// instead
+type TableCellNode struct {
+	*BaseNode
+	rowSpan int
+	colSpan int
+}
+
+// NewTableCellNode creates a new, empty TableCellNode spanning one row and
+// one column
+func NewTableCellNode() *TableCellNode {
+	return &TableCellNode{
+		BaseNode: NewBaseNode(NodeTableCell),
+		rowSpan:  1,
+		colSpan:  1,
+	}
+}
+
+// RowSpan returns the number of rows the cell spans
+func (n *TableCellNode) RowSpan() int { return n.rowSpan }
+
+// ColSpan returns the number of columns the cell spans
+func (n *TableCellNode) ColSpan() int { return n.colSpan }
+
+// SetSpan sets the number of rows and columns the cell spans
+func (n *TableCellNode) SetSpan(rows, columns int) {
+	n.rowSpan = max(rows, 1)
+	n.colSpan = max(columns, 1)
+}
+
+// String representation for debugging
+func (n *TableCellNode) String() string {
+	return fmt.Sprintf("Table Cell %dx%d", n.rowSpan, n.colSpan)
 }
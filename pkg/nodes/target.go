@@ -0,0 +1,61 @@
+package nodes
+
+import "fmt"
+
+// TargetNode represents a hyperlink target (".. _name: URI"). An external
+// target has a URI, an indirect target refers to another target by name
+// (".. _a: b_"), and an internal target, without either, marks the element
+// that follows it.
+type TargetNode struct {
+	*BaseNode
+	name      string
+	anonymous bool
+	uri       string
+	refName   string
+}
+
+// NewTargetNode creates a new TargetNode with the given normalized name,
+// which is empty for an anonymous target
+func NewTargetNode(name string) *TargetNode {
+	return &TargetNode{
+		BaseNode:  NewBaseNode(NodeTarget),
+		name:      name,
+		anonymous: name == "",
+	}
+}
+
+// Name returns the normalized name references match, or "" for an anonymous
+// target
+func (n *TargetNode) Name() string { return n.name }
+
+// IsAnonymous returns true for anonymous targets (".. __: URI"), which
+// anonymous references match in order
+func (n *TargetNode) IsAnonymous() bool { return n.anonymous }
+
+// URI returns the URI of an external target
+func (n *TargetNode) URI() string { return n.uri }
+
+// SetURI sets the URI of the target
+func (n *TargetNode) SetURI(uri string) { n.uri = uri }
+
+// RefName returns the normalized name of the target an indirect target
+// refers to
+func (n *TargetNode) RefName() string { return n.refName }
+
+// SetRefName sets the name of the target an indirect target refers to
+func (n *TargetNode) SetRefName(name string) { n.refName = name }
+
+// IsInternal returns true for a target that refers to a place in the
+// document rather than a URI or another target
+func (n *TargetNode) IsInternal() bool { return n.uri == "" && n.refName == "" }
+
+// String representation for debugging
+func (n *TargetNode) String() string {
+	switch {
+	case n.uri != "":
+		return fmt.Sprintf("Target[%s]: %s", n.name, n.uri)
+	case n.refName != "":
+		return fmt.Sprintf("Target[%s]: %s_", n.name, n.refName)
+	}
+	return fmt.Sprintf("Target[%s]", n.name)
+}
@@ -0,0 +1,22 @@
+package nodes
+
+import "fmt"
+
+// TextNode represents a run of plain inline text
+type TextNode struct {
+	*BaseNode
+}
+
+// NewTextNode creates a new TextNode with the given content
+func NewTextNode(content string) *TextNode {
+	node := &TextNode{
+		BaseNode: NewBaseNode(NodeText),
+	}
+	node.SetContent(content)
+	return node
+}
+
+// String representation for debugging
+func (n *TextNode) String() string {
+	return fmt.Sprintf("Text: %s", n.Content())
+}
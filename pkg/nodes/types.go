@@ -2,31 +2,70 @@
 
 package nodes
 
+import "fmt"
+
 // NodeType represents the type of a node in the RST document structure
 type NodeType int
 
 // Node type constants define the possible types of nodes in the RST document tree
 const (
-	NodeHeading    NodeType = iota // Represents a section heading
-	NodeParagraph                  // Represents a text paragraph
-	NodeList                       // Represents an ordered or unordered list
-	NodeListItem                   // Represents an item within a list
-	NodeLink                       // Represents a hyperlink
-	NodeEmphasis                   // Represents emphasized (italic) text
-	NodeStrong                     // Represents strong (bold) text
-	NodeMeta                       // Represents metadata information
-	NodeDirective                  // Represents an RST directive
-	NodeCode                       // Represents a code block
-	NodeTable                      // Represents a table structure
-	NodeBlockQuote                 // Represents a block quote
-	NodeDoctest                    // Represents a doctest block
-	NodeLineBlock                  // Represents a line block
-	NodeComment                    // Represents a comment
-	NodeTitle                      // Represents a document title
-	NodeSubtitle                   // Represents a document subtitle
-	NodeTransition
+	NodeHeading               NodeType = iota // Represents a section heading
+	NodeParagraph                             // Represents a text paragraph
+	NodeList                                  // Represents an ordered or unordered list
+	NodeListItem                              // Represents an item within a list
+	NodeLink                                  // Represents a hyperlink
+	NodeEmphasis                              // Represents emphasized (italic) text
+	NodeStrong                                // Represents strong (bold) text
+	NodeMeta                                  // Represents metadata information
+	NodeDirective                             // Represents an RST directive
+	NodeCode                                  // Represents a code block
+	NodeTable                                 // Represents a table structure
+	NodeBlockQuote                            // Represents a block quote
+	NodeDoctest                               // Represents a doctest block
+	NodeLineBlock                             // Represents a line block
+	NodeComment                               // Represents a comment
+	NodeTitle                                 // Represents a document title
+	NodeSubtitle                              // Represents a document subtitle
+	NodeTransition                            // Represents a transition between sections
+	NodeText                                  // Represents plain inline text
+	NodeLiteral                               // Represents an inline literal
+	NodeInterpretedText                       // Represents interpreted text with an optional role
+	NodeSubstitutionReference                 // Represents a substitution reference
+	NodeSection                               // Represents a section with its heading and body
+	NodeDefinitionList                        // Represents a definition list
+	NodeDefinitionListItem                    // Represents a term and its definition
+	NodeTerm                                  // Represents the term of a definition list item
+	NodeFieldList                             // Represents a field list
+	NodeField                                 // Represents a field name and body
+	NodeDocInfo                               // Represents the bibliographic fields of a document
+	NodeOptionList                            // Represents a list of command-line options
+	NodeOptionListItem                        // Represents an option group and its description
+	NodeTableRow                              // Represents a row of a table
+	NodeTableCell                             // Represents a table cell, which may span rows and columns
+	NodeImage                                 // Represents an image
+	NodeAdmonition                            // Represents an admonition such as a note or warning
+	NodeInline                                // Represents inline text produced by an interpreted text role
+	NodeFootnote                              // Represents a footnote or citation
+	NodeFootnoteReference                     // Represents a reference to a footnote or citation
+	NodeTarget                                // Represents a hyperlink target
+	NodeRaw                                   // Represents raw content passed through to writers of a format
 )
 
+// Position identifies a location in the source text
+type Position struct {
+	Line   int // 1-based line number
+	Column int // 1-based column, counted in characters after tab expansion
+	Offset int // 0-based byte offset from the start of the source
+}
+
+// IsValid reports whether the position has been set
+func (p Position) IsValid() bool { return p.Line > 0 }
+
+// String returns the position as "line:column"
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
 // Node interface defines the common behavior for all RST document nodes
 type Node interface {
 	// Type returns the NodeType of this node
@@ -43,6 +82,17 @@ type Node interface {
 	Children() []Node
 	// AddChild adds a child node to this node
 	AddChild(Node)
+	// Start returns the source position where the node begins
+	Start() Position
+	// End returns the source position just past the end of the node
+	End() Position
+	// SetPosition sets the source span of the node
+	SetPosition(start, end Position)
+	// ID returns the identifier links use to refer to the node, or "" if
+	// it has none
+	ID() string
+	// SetID sets the identifier of the node
+	SetID(string)
 }
 
 // BaseNode provides the basic implementation of the Node interface
@@ -52,6 +102,9 @@ type BaseNode struct {
 	content  string
 	level    int
 	children []Node
+	start    Position
+	end      Position
+	id       string
 }
 
 // NewBaseNode creates a new BaseNode with the specified node type
@@ -96,3 +149,23 @@ func (n *BaseNode) Children() []Node {
 func (n *BaseNode) AddChild(child Node) {
 	n.children = append(n.children, child)
 }
+
+// Start returns the source position where the node begins
+func (n *BaseNode) Start() Position { return n.start }
+
+// End returns the source position just past the end of the node
+func (n *BaseNode) End() Position { return n.end }
+
+// SetPosition sets the source span of the node
+func (n *BaseNode) SetPosition(start, end Position) {
+	n.start = start
+	n.end = end
+}
+
+// ID returns the identifier links use to refer to the node
+func (n *BaseNode) ID() string { return n.id }
+
+// SetID sets the identifier of the node
+func (n *BaseNode) SetID(id string) {
+	n.id = id
+}
@@ -1,19 +1,45 @@
 package parser
 
-import "github.com/go-i2p/go-rst/pkg/nodes"
+import (
+	"strings"
 
-func (p *Parser) processBlockQuote(content string, args []string, currentNode nodes.Node) nodes.Node {
-	attribution := ""
-	if len(args) > 0 {
-		attribution = args[0]
-	}
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// processBlockQuote parses an indented block into a block quote whose body is
+// parsed recursively.
+func (p *Parser) processBlockQuote(r *lineReader) *nodes.BlockQuoteNode {
+	lines, indent := r.indentedBlock()
+	p.checkBlankFinish(r, "Block quote")
+	body, attribution := splitAttribution(lines)
 
-	if currentNode != nil && currentNode.Type() == nodes.NodeBlockQuote {
-		// Add to existing block quote
-		blockQuoteNode := currentNode.(*nodes.BlockQuoteNode)
-		blockQuoteNode.AppendContent(content)
-		return blockQuoteNode
+	quote := nodes.NewBlockQuoteNode("", attribution)
+	for _, child := range p.parseNested(body, indent) {
+		quote.AddChild(child)
 	}
+	return quote
+}
 
-	return nodes.NewBlockQuoteNode(content, attribution)
+// splitAttribution separates a trailing attribution ("-- Author") from the
+// body of a block quote. The attribution must follow a blank line.
+func splitAttribution(lines []sourceLine) ([]sourceLine, string) {
+	for i := len(lines) - 1; i > 0; i-- {
+		if lines[i].isBlank() {
+			return lines, ""
+		}
+		text := lines[i].text
+		if lines[i].indent() > 0 || !lines[i-1].isBlank() {
+			continue
+		}
+		for _, dash := range []string{"-- ", "--- ", "— "} {
+			if strings.HasPrefix(text, dash) {
+				parts := []string{strings.TrimSpace(text[len(dash):])}
+				for _, line := range lines[i+1:] {
+					parts = append(parts, strings.TrimSpace(line.text))
+				}
+				return lines[:i], strings.Join(parts, " ")
+			}
+		}
+	}
+	return lines, ""
 }
@@ -0,0 +1,23 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// processComment parses an explicit markup block that is not a directive. The
+// comment text includes any indented lines that follow the ".." marker.
+func (p *Parser) processComment(r *lineReader, token Token) *nodes.CommentNode {
+	first := r.next()
+	// An empty ".." followed by a blank line is an empty comment; an indented
+	// block after the blank line is then a block quote.
+	if token.Content == "" {
+		if next, ok := r.peekAt(0); !ok || next.isBlank() {
+			return nodes.NewCommentNode("")
+		}
+	}
+	body, _ := r.firstKnownIndented(sourceLine{text: token.Content, number: first.number})
+	p.checkExplicitFinish(r)
+	return nodes.NewCommentNode(strings.TrimSpace(joinLines(body)))
+}
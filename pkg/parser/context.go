@@ -2,27 +2,73 @@ package parser
 
 // ParserContext represents the current state of the parser.
 type ParserContext struct {
-	inMeta           bool
-	inDirective      bool
-	currentDirective string
-	inCodeBlock      bool
-	codeBlockIndent  int
-	buffer           []string
+	// indents holds the indentation removed for each enclosing nested block,
+	// innermost last.
+	indents []int
+
+	// styles holds the section title adornment styles in the order they
+	// first appeared; the style at index i marks sections of level i+1.
+	styles []adornmentStyle
+	// section is the level of the current section, or 0 before any title.
+	section int
 }
 
 // NewParserContext creates a new ParserContext instance.
 func NewParserContext() *ParserContext {
 	return &ParserContext{
-		buffer: make([]string, 0),
+		indents: make([]int, 0),
 	}
 }
 
 // Reset resets the parser context to its initial state.
 func (c *ParserContext) Reset() {
-	c.inMeta = false
-	c.inDirective = false
-	c.currentDirective = ""
-	c.inCodeBlock = false
-	c.codeBlockIndent = 0
-	c.buffer = c.buffer[:0]
+	c.indents = c.indents[:0]
+	c.styles = c.styles[:0]
+	c.section = 0
+}
+
+// pushIndent records entry into a nested block indented by indent columns.
+func (c *ParserContext) pushIndent(indent int) {
+	c.indents = append(c.indents, indent)
+}
+
+// popIndent records leaving the innermost nested block.
+func (c *ParserContext) popIndent() {
+	c.indents = c.indents[:len(c.indents)-1]
+}
+
+// Depth returns the number of nested blocks enclosing the current position.
+func (c *ParserContext) Depth() int {
+	return len(c.indents)
+}
+
+// Indent returns the total indentation of the current block.
+func (c *ParserContext) Indent() int {
+	total := 0
+	for _, indent := range c.indents {
+		total += indent
+	}
+	return total
+}
+
+// sectionLevel returns the level of a section title with the given adornment
+// style and makes it the current section. Styles are assigned levels in the
+// order they first appear. It reports false, leaving the context unchanged,
+// if the title would skip a level below the current section.
+func (c *ParserContext) sectionLevel(style adornmentStyle) (int, bool) {
+	for i, known := range c.styles {
+		if known == style {
+			if i+1 > c.section+1 {
+				return i + 1, false
+			}
+			c.section = i + 1
+			return c.section, true
+		}
+	}
+	if len(c.styles) > c.section {
+		return len(c.styles) + 1, false
+	}
+	c.styles = append(c.styles, style)
+	c.section = len(c.styles)
+	return c.section, true
 }
@@ -0,0 +1,364 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// csvDialect describes the CSV syntax of a csv-table directive.
+type csvDialect struct {
+	delimiter rune
+	quote     rune
+	escape    rune // zero if quotes are escaped by doubling them
+}
+
+// runCSVTable builds a table from CSV data given as the content of a
+// csv-table directive or read from the file named by its "file" option,
+// relative to the document. The directive arguments form the table title.
+// Each cell is parsed as reStructuredText. On error the directive is reported
+// and kept as a literal block.
+func runCSVTable(ctx *DirectiveContext) ([]nodes.Node, error) {
+	table, message := ctx.parser.buildCSVTable(ctx.line, ctx.Arguments, ctx.Options, ctx.content)
+	if table == nil {
+		return nil, errors.New(message)
+	}
+	return []nodes.Node{table}, nil
+}
+
+// buildCSVTable does the work of runCSVTable. It returns nil and a
+// message if the directive is in error.
+func (p *Parser) buildCSVTable(line sourceLine, args []string, options nodes.DirectiveOptions, content []sourceLine) (*nodes.TableNode, string) {
+	dialect, message := parseCSVDialect(options)
+	if message != "" {
+		return nil, message
+	}
+
+	// The data comes with the source lines its records start on, so that
+	// cells can be given a position.
+	var data string
+	var lines []sourceLine
+	file, hasFile := options["file"]
+	switch {
+	case hasFile && len(content) > 0:
+		return nil, `The "csv-table" directive may not both specify an external file and have content.`
+	case hasFile:
+		if !p.fileInsertion {
+			return nil, `File and URL access deactivated; ignoring "file" option in "csv-table" directive.`
+		}
+		raw, err := os.ReadFile(p.sourceRelative(file))
+		if err != nil {
+			return nil, fmt.Sprintf("Problems with \"csv-table\" directive path:\n%v.", err)
+		}
+		if data, err = decodeText(raw, options["encoding"]); err != nil {
+			return nil, fmt.Sprintf("Problems with \"csv-table\" directive path:\n%v.", err)
+		}
+	case len(content) > 0:
+		data, lines = joinLines(content), content
+	default:
+		return nil, `The "csv-table" directive requires content; none supplied.`
+	}
+
+	rows, rowLines, err := parseCSV(data, dialect)
+	if err != nil {
+		return nil, fmt.Sprintf("Error with CSV data in \"csv-table\" directive:\n%v", err)
+	}
+	rowSources := make([]sourceLine, len(rows))
+	for i := range rows {
+		rowSources[i] = line
+		if lines != nil {
+			rowSources[i] = lines[rowLines[i]]
+		}
+	}
+
+	headerRows := 0
+	if header, ok := options["header"]; ok {
+		headers, _, err := parseCSV(header, csvDialect{delimiter: ',', quote: '"', escape: '\\'})
+		if err != nil {
+			return nil, fmt.Sprintf("Error with CSV data in \"csv-table\" directive:\n%v", err)
+		}
+		headerRows = len(headers)
+		rows = append(headers, rows...)
+		rowSources = append(repeatLine(line, len(headers)), rowSources...)
+	}
+	count, message := tableCount(options, "csv-table", "header-rows")
+	if message != "" {
+		return nil, message
+	}
+	if count > len(rows)-headerRows {
+		return nil, fmt.Sprintf(`%d header row(s) specified but only %d row(s) of data supplied ("csv-table" directive).`,
+			count, len(rows)-headerRows)
+	}
+	headerRows += count
+
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	table := nodes.NewTableNode()
+	table.SetTitle(strings.Join(args, " "))
+	if message := setTableWidths(table, "csv-table", options, columns); message != "" {
+		return nil, message
+	}
+	for i, row := range rows {
+		tableRow := nodes.NewTableRowNode()
+		tableRow.SetPosition(spanLines(rowSources[i : i+1]))
+		// Short rows are padded with empty cells.
+		for j := 0; j < columns; j++ {
+			cell := nodes.NewTableCellNode()
+			cell.SetPosition(tableRow.Start(), tableRow.End())
+			if j < len(row) {
+				p.addCellText(cell, row[j], rowSources[i])
+			}
+			tableRow.AppendCell(cell)
+		}
+		if i < headerRows {
+			table.AddHeaderRow(tableRow)
+		} else {
+			table.AddBodyRow(tableRow)
+		}
+	}
+	return table, ""
+}
+
+// addCellText parses the text of a table cell that has no exact source
+// location, such as CSV data, into the children of cell. The nodes are placed
+// on the source line the cell's row starts on.
+func (p *Parser) addCellText(cell *nodes.TableCellNode, text string, source sourceLine) {
+	texts := strings.Split(text, "\n")
+	lines := make([]sourceLine, len(texts))
+	for i, t := range texts {
+		lines[i] = source
+		lines[i].text = expandIndent(strings.TrimRight(t, " \t\r"))
+		lines[i].pad = 0
+	}
+	for _, child := range p.parseNested(lines, 0) {
+		placeAt(child, cell.Start(), cell.End())
+		cell.AddChild(child)
+	}
+}
+
+// placeAt sets the span of node and all its descendants.
+func placeAt(node nodes.Node, start, end nodes.Position) {
+	node.SetPosition(start, end)
+	for _, child := range node.Children() {
+		placeAt(child, start, end)
+	}
+}
+
+// repeatLine returns a slice of n copies of line.
+func repeatLine(line sourceLine, n int) []sourceLine {
+	lines := make([]sourceLine, n)
+	for i := range lines {
+		lines[i] = line
+	}
+	return lines
+}
+
+// parseCSVDialect reads the "delim", "quote" and "escape" options of a
+// csv-table directive. It returns a message if an option is invalid.
+func parseCSVDialect(options nodes.DirectiveOptions) (csvDialect, string) {
+	dialect := csvDialect{delimiter: ',', quote: '"'}
+	chars := []*rune{&dialect.delimiter, &dialect.quote, &dialect.escape}
+	for i, name := range []string{"delim", "quote", "escape"} {
+		value, ok := options[name]
+		if !ok {
+			continue
+		}
+		r, err := directiveChar(value)
+		if err != nil {
+			return dialect, fmt.Sprintf(`Error in "csv-table" directive: invalid option value: (option: %q; value: %q) %v.`, name, value, err)
+		}
+		*chars[i] = r
+	}
+	return dialect, ""
+}
+
+// directiveChar parses an option value naming a single character: the
+// character itself, "tab", "space", or a code point such as "0x09" or
+// "U+0009".
+func directiveChar(value string) (rune, error) {
+	switch value {
+	case "tab":
+		return '\t', nil
+	case "space":
+		return ' ', nil
+	}
+	lower := strings.ToLower(value)
+	for _, prefix := range []string{"0x", "u+", "x", "\\x", "\\u"} {
+		if strings.HasPrefix(lower, prefix) && len(lower) > len(prefix) {
+			if code, err := strconv.ParseUint(lower[len(prefix):], 16, 32); err == nil {
+				return rune(code), nil
+			}
+		}
+	}
+	if utf8.RuneCountInString(value) != 1 {
+		return 0, errors.New("must be a single character or a Unicode code")
+	}
+	r, _ := utf8.DecodeRuneInString(value)
+	return r, nil
+}
+
+// setTableWidths sets the column widths of a table from the "widths" option
+// of a table directive. It returns a message if the option is invalid.
+func setTableWidths(table *nodes.TableNode, directive string, options nodes.DirectiveOptions, columns int) string {
+	value, ok := options["widths"]
+	if !ok || value == "auto" || value == "grid" {
+		return ""
+	}
+	widths, err := parseWidths(value)
+	if err != nil {
+		return fmt.Sprintf(`Error in %q directive: invalid option value: (option: "widths"; value: %q) %v.`, directive, value, err)
+	}
+	if len(widths) != columns {
+		return fmt.Sprintf(`%q widths do not match the number of columns in table (%d).`, directive, columns)
+	}
+	table.SetColumnWidths(widths)
+	return ""
+}
+
+// tableCount parses a non-negative integer option of a table directive, such
+// as "header-rows". A missing option counts as zero. It returns a message if
+// the option is invalid.
+func tableCount(options nodes.DirectiveOptions, directive, name string) (int, string) {
+	value, ok := options[name]
+	if !ok {
+		return 0, ""
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return 0, fmt.Sprintf(`Error in %q directive: invalid option value: (option: %q; value: %q).`, directive, name, value)
+	}
+	return count, ""
+}
+
+// parseWidths parses the "widths" option of a table directive: a list of
+// positive integers separated by commas or whitespace.
+func parseWidths(value string) ([]int, error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	widths := make([]int, len(fields))
+	for i, field := range fields {
+		width, err := strconv.Atoi(field)
+		if err != nil || width <= 0 {
+			return nil, fmt.Errorf("invalid column width %q", field)
+		}
+		widths[i] = width
+	}
+	if len(widths) == 0 {
+		return nil, errors.New("no column widths given")
+	}
+	return widths, nil
+}
+
+// decodeText converts the contents of a file in the given encoding to a
+// string. UTF-8, with or without a byte order mark, is the default; Latin-1
+// and ASCII are also understood.
+func decodeText(data []byte, encoding string) (string, error) {
+	switch strings.ReplaceAll(strings.ToLower(encoding), "_", "-") {
+	case "", "utf-8", "utf8", "utf-8-sig":
+		if !utf8.Valid(data) {
+			return "", errors.New("input is not valid UTF-8")
+		}
+		return strings.TrimPrefix(string(data), byteOrderMark), nil
+	case "latin-1", "latin1", "iso-8859-1", "iso8859-1":
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes), nil
+	case "ascii", "us-ascii":
+		for _, b := range data {
+			if b >= utf8.RuneSelf {
+				return "", errors.New("input is not valid ASCII")
+			}
+		}
+		return string(data), nil
+	}
+	return "", fmt.Errorf("unknown encoding %q", encoding)
+}
+
+// parseCSV splits CSV data into records of fields, following the Python csv
+// module as used by docutils: spaces after a delimiter are skipped, a quoted
+// field may span lines and contain doubled quotes, and the escape character,
+// if any, makes the next character literal. It also returns the index of the
+// line each record starts on.
+func parseCSV(data string, dialect csvDialect) ([][]string, []int, error) {
+	records := make([][]string, 0)
+	starts := make([]int, 0)
+	var record []string
+	var field strings.Builder
+	line, start := 0, 0
+	quoted, wasQuoted, atFieldStart := false, false, true
+
+	endField := func() {
+		record = append(record, field.String())
+		field.Reset()
+		wasQuoted, atFieldStart = false, true
+	}
+	endRecord := func() {
+		if len(record) > 0 || field.Len() > 0 || wasQuoted || !atFieldStart {
+			endField()
+		}
+		records = append(records, record)
+		starts = append(starts, start)
+		record = nil
+	}
+
+	runes := []rune(data)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case dialect.escape != 0 && r == dialect.escape:
+			if i+1 >= len(runes) {
+				return nil, nil, fmt.Errorf("line %d: unexpected end of data after escape character", line+1)
+			}
+			i++
+			if runes[i] == '\n' {
+				line++
+			}
+			field.WriteRune(runes[i])
+			atFieldStart = false
+		case quoted:
+			switch {
+			case r == dialect.quote && i+1 < len(runes) && runes[i+1] == dialect.quote:
+				field.WriteRune(r)
+				i++
+			case r == dialect.quote:
+				quoted = false
+				if i+1 < len(runes) && runes[i+1] != dialect.delimiter && runes[i+1] != '\n' {
+					return nil, nil, fmt.Errorf("line %d: %q expected after %q", line+1, dialect.delimiter, dialect.quote)
+				}
+			default:
+				if r == '\n' {
+					line++
+				}
+				field.WriteRune(r)
+			}
+		case r == dialect.quote && atFieldStart:
+			quoted, wasQuoted, atFieldStart = true, true, false
+		case r == dialect.delimiter:
+			endField()
+		case r == '\n':
+			endRecord()
+			line++
+			start = line
+		case r == ' ' && atFieldStart:
+			// Skip initial spaces
+		default:
+			field.WriteRune(r)
+			atFieldStart = false
+		}
+	}
+	if quoted {
+		return nil, nil, fmt.Errorf("line %d: unexpected end of data in quoted field", line+1)
+	}
+	if len(runes) > 0 && runes[len(runes)-1] != '\n' {
+		endRecord()
+	}
+	return records, starts, nil
+}
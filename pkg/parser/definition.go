@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// isDefinitionListItem reports whether the current line is a term: a line of
+// text directly followed by an indented definition.
+func (p *Parser) isDefinitionListItem(r *lineReader) bool {
+	line := r.peek()
+	if line.indent() > 0 || p.lexer.Tokenize(line.text).Type != TokenText {
+		return false
+	}
+	next, ok := r.peekAt(1)
+	return ok && !next.isBlank() && next.indent() > 0
+}
+
+// processDefinitionList parses consecutive terms and their indented
+// definitions into a DefinitionListNode. A term may be followed by
+// classifiers, each introduced by " : ".
+func (p *Parser) processDefinitionList(r *lineReader) *nodes.DefinitionListNode {
+	list := nodes.NewDefinitionListNode()
+	for {
+		end := r.pos
+		r.skipBlank()
+		if r.eof() {
+			break
+		}
+		if !p.isDefinitionListItem(r) {
+			if r.pos == end {
+				p.checkBlankFinish(r, "Definition list")
+			}
+			break
+		}
+		start := r.pos
+		line := r.next()
+		body, indent := r.indentedBlock()
+
+		item := p.newDefinitionListItem(line)
+		item.SetPosition(spanLines(r.lines[start:r.pos]))
+		for _, child := range p.parseNested(body, indent) {
+			item.AddChild(child)
+		}
+		list.AppendChild(item)
+	}
+	return list
+}
+
+// newDefinitionListItem parses the term line of a definition list item. Only
+// " : " delimiters outside inline markup separate classifiers.
+func (p *Parser) newDefinitionListItem(line sourceLine) *nodes.DefinitionListItemNode {
+	inline := p.parseInline(line.text, []sourceLine{line})
+	var classifiers []string
+	for i, node := range inline {
+		text, ok := node.(*nodes.TextNode)
+		if !ok {
+			continue
+		}
+		loc := p.patterns.classifier.FindStringIndex(text.Content())
+		if loc == nil {
+			continue
+		}
+		rest := text.Content()[loc[1]:]
+		for _, node := range inline[i+1:] {
+			rest += nodes.TextContent(node)
+		}
+		classifiers = p.patterns.classifier.Split(rest, -1)
+
+		before := text.Content()[:loc[0]]
+		inline = inline[:i]
+		if before != "" {
+			termText := nodes.NewTextNode(before)
+			termText.SetPosition(text.Start(), text.End())
+			inline = append(inline, termText)
+		}
+		break
+	}
+
+	var content strings.Builder
+	term := nodes.NewTermNode("")
+	for _, node := range inline {
+		content.WriteString(nodes.TextContent(node))
+		term.AddChild(node)
+	}
+	term.SetContent(content.String())
+	if len(inline) > 0 {
+		term.SetPosition(inline[0].Start(), inline[len(inline)-1].End())
+	}
+
+	item := nodes.NewDefinitionListItemNode(term)
+	for _, classifier := range classifiers {
+		item.AddClassifier(strings.TrimSpace(classifier))
+	}
+	return item
+}
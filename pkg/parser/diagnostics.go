@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// Level is the severity of a diagnostic, modeled on the docutils system
+// message levels.
+type Level int
+
+const (
+	LevelInfo    Level = iota + 1 // LevelInfo reports information that needs no action.
+	LevelWarning                  // LevelWarning reports a minor problem; output is still usable.
+	LevelError                    // LevelError reports a problem that should be fixed.
+	LevelSevere                   // LevelSevere reports input that could not be processed sensibly.
+	LevelNone                     // LevelNone is a halt level that never halts.
+)
+
+// String returns the docutils name of the level.
+func (l Level) String() string {
+	switch l {
+	case LevelInfo:
+		return "INFO"
+	case LevelWarning:
+		return "WARNING"
+	case LevelError:
+		return "ERROR"
+	case LevelSevere:
+		return "SEVERE"
+	case LevelNone:
+		return "NONE"
+	}
+	return fmt.Sprintf("LEVEL%d", int(l))
+}
+
+// ParseLevel returns the level with the given name, such as "warning" or
+// "error", ignoring case.
+func ParseLevel(name string) (Level, error) {
+	for level := LevelInfo; level <= LevelNone; level++ {
+		if strings.EqualFold(name, level.String()) {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown diagnostic level %q", name)
+}
+
+// Diagnostic is a message about a problem found in the input.
+type Diagnostic struct {
+	Level    Level
+	Message  string
+	Position nodes.Position
+}
+
+// String formats the diagnostic like a docutils system message.
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: (%s/%d) %s", d.Position, d.Level, int(d.Level), d.Message)
+}
+
+// HaltError is returned when a diagnostic reaches the parser's halt level.
+type HaltError struct {
+	Diagnostic Diagnostic
+}
+
+// Error implements the error interface.
+func (e *HaltError) Error() string {
+	return fmt.Sprintf("parsing halted: %s", e.Diagnostic)
+}
+
+// report records a diagnostic. Parsing stops once a diagnostic reaches the
+// halt level.
+func (p *Parser) report(level Level, pos nodes.Position, format string, args ...interface{}) {
+	diagnostic := Diagnostic{
+		Level:    level,
+		Message:  fmt.Sprintf(format, args...),
+		Position: pos,
+	}
+	p.diagnostics = append(p.diagnostics, diagnostic)
+	if level >= p.haltLevel && p.halt == nil {
+		p.halt = &HaltError{Diagnostic: diagnostic}
+	}
+}
+
+// checkBlankFinish warns when a construct is directly followed by a line with
+// less indentation instead of a blank line.
+func (p *Parser) checkBlankFinish(r *lineReader, construct string) {
+	if r.eof() || r.peek().isBlank() {
+		return
+	}
+	line := r.peek()
+	p.report(LevelWarning, line.position(line.indent()),
+		"%s ends without a blank line; unexpected unindent.", construct)
+}
+
+// checkExplicitFinish is checkBlankFinish for explicit markup blocks, which
+// may follow each other without a blank line between them.
+func (p *Parser) checkExplicitFinish(r *lineReader) {
+	if !r.eof() && (p.patterns.comment.MatchString(r.peek().text) || p.patterns.anonymousTarget.MatchString(r.peek().text)) {
+		return
+	}
+	p.checkBlankFinish(r, "Explicit markup")
+}
@@ -1,26 +1,376 @@
 package parser
 
 import (
+	"fmt"
 	"strings"
+	"unicode"
 
 	"github.com/go-i2p/go-rst/pkg/nodes"
 )
 
-func (p *Parser) processDirectiveContent(line string, currentNode nodes.Node) nodes.Node {
-	if currentNode == nil || currentNode.Type() != nodes.NodeDirective {
-		return currentNode
+var (
+	// commonOptions are accepted by most directives
+	commonOptions = OptionSpec{"class": ClassOption, "name": Unchanged}
+	imageOptions  = commonOptions.with(OptionSpec{
+		"alt":     Unchanged,
+		"height":  LengthOrPercentage,
+		"width":   LengthOrPercentage,
+		"scale":   NonNegativeInt,
+		"align":   Choice("top", "middle", "bottom", "left", "center", "right"),
+		"target":  URI,
+		"loading": Choice("embed", "link", "lazy"),
+	})
+	figureOptions = imageOptions.with(OptionSpec{
+		"figwidth": Unchanged,
+		"figclass": ClassOption,
+		"align":    Choice("left", "center", "right"),
+	})
+	codeOptions = commonOptions.with(OptionSpec{
+		"number-lines":    Unchanged,
+		"linenos":         Flag,
+		"lineno-start":    NonNegativeInt,
+		"emphasize-lines": Unchanged,
+		"caption":         Unchanged,
+	})
+	tableOptions = commonOptions.with(OptionSpec{
+		"align":  Choice("left", "center", "right"),
+		"width":  LengthOrPercentage,
+		"widths": Unchanged,
+	})
+	listTableOptions = tableOptions.with(OptionSpec{
+		"header-rows":  NonNegativeInt,
+		"stub-columns": NonNegativeInt,
+	})
+	csvTableOptions = listTableOptions.with(OptionSpec{
+		"header":    Unchanged,
+		"file":      UnchangedRequired,
+		"url":       URI,
+		"encoding":  UnchangedRequired,
+		"delim":     UnchangedRequired,
+		"quote":     UnchangedRequired,
+		"escape":    UnchangedRequired,
+		"keepspace": Flag,
+	})
+	// roleOptions are the options of the standard roles that the role
+	// directive may set on a derived role
+	roleOptions = OptionSpec{
+		"class":    ClassOption,
+		"language": UnchangedRequired,
+		"format":   UnchangedRequired,
 	}
+	includeOptions = commonOptions.with(OptionSpec{
+		"literal":      Flag,
+		"code":         Unchanged,
+		"encoding":     UnchangedRequired,
+		"parser":       UnchangedRequired,
+		"tab-width":    NonNegativeInt,
+		"start-line":   Integer,
+		"end-line":     Integer,
+		"start-after":  UnchangedRequired,
+		"end-before":   UnchangedRequired,
+		"number-lines": Unchanged,
+	})
+)
+
+// standardDirectives returns the docutils directives understood by the
+// parser or the renderers. Admonitions, images, code, tables, raw content
+// and includes build their own nodes; the body elements become directive
+// nodes with their content parsed. Directives the parser does not implement
+// are still recognized, and reported when used.
+func standardDirectives() map[string]Directive {
+	admonition := Directive{HasContent: true, Options: commonOptions, Run: runAdmonition}
+	body := Directive{HasContent: true, Options: commonOptions, Run: runDirective}
+	titled := Directive{RequiredArguments: 1, FinalArgumentWhitespace: true, HasContent: true, Options: commonOptions, Run: runDirective}
+	captioned := Directive{OptionalArguments: 1, FinalArgumentWhitespace: true, HasContent: true, Options: commonOptions, Run: runDirective}
+	code := Directive{OptionalArguments: 1, HasContent: true, Options: codeOptions, Run: runCode}
+	sectnum := Directive{Options: OptionSpec{"depth": NonNegativeInt, "prefix": Unchanged, "suffix": Unchanged, "start": NonNegativeInt}, Run: unsupported(false)}
+
+	return map[string]Directive{
+		"admonition": {RequiredArguments: 1, FinalArgumentWhitespace: true, HasContent: true, Options: commonOptions, Run: runAdmonition},
+		"attention":  admonition,
+		"caution":    admonition,
+		"danger":     admonition,
+		"error":      admonition,
+		"hint":       admonition,
+		"important":  admonition,
+		"note":       admonition,
+		"tip":        admonition,
+		"warning":    admonition,
+
+		"image":  {RequiredArguments: 1, FinalArgumentWhitespace: true, Options: imageOptions, Run: runImage},
+		"figure": {RequiredArguments: 1, FinalArgumentWhitespace: true, HasContent: true, Options: figureOptions, Run: runFigure},
+
+		"topic":          titled,
+		"sidebar":        {OptionalArguments: 1, FinalArgumentWhitespace: true, HasContent: true, Options: commonOptions.with(OptionSpec{"subtitle": UnchangedRequired}), Run: runDirective},
+		"line-block":     body,
+		"parsed-literal": body,
+		"code":           code,
+		"code-block":     code,
+		"sourcecode":     code,
+		"math":           {HasContent: true, Options: commonOptions, Run: unsupported(false)},
+		"rubric":         {RequiredArguments: 1, FinalArgumentWhitespace: true, Options: commonOptions, Run: runDirective},
+		"epigraph":       body,
+		"highlights":     body,
+		"pull-quote":     body,
+		"compound":       body,
+		"container":      captioned,
+
+		"table":      {OptionalArguments: 1, FinalArgumentWhitespace: true, HasContent: true, Options: tableOptions, Run: runDirective},
+		"csv-table":  {OptionalArguments: 1, FinalArgumentWhitespace: true, HasContent: true, Options: csvTableOptions, Run: runCSVTable},
+		"list-table": {OptionalArguments: 1, FinalArgumentWhitespace: true, HasContent: true, Options: listTableOptions, Run: runListTable},
+
+		"contents":          {OptionalArguments: 1, FinalArgumentWhitespace: true, Options: OptionSpec{"depth": NonNegativeInt, "local": Flag, "backlinks": Choice("entry", "top", "none"), "class": ClassOption}, Run: unsupported(false)},
+		"sectnum":           sectnum,
+		"section-numbering": sectnum,
+		"header":            {HasContent: true, Run: unsupported(true)},
+		"footer":            {HasContent: true, Run: unsupported(true)},
+		"target-notes":      {Options: commonOptions, Run: unsupported(false)},
+
+		"meta":         {HasContent: true, Run: unsupported(true)},
+		"replace":      {HasContent: true, Run: runReplace},
+		"unicode":      {RequiredArguments: 1, FinalArgumentWhitespace: true, Options: OptionSpec{"ltrim": Flag, "rtrim": Flag, "trim": Flag}, Run: runUnicode},
+		"date":         {OptionalArguments: 1, FinalArgumentWhitespace: true, Run: runDate},
+		"include":      {RequiredArguments: 1, FinalArgumentWhitespace: true, Options: includeOptions, Run: runInclude},
+		"raw":          {RequiredArguments: 1, FinalArgumentWhitespace: true, HasContent: true, Options: OptionSpec{"file": UnchangedRequired, "url": URI, "encoding": UnchangedRequired, "class": ClassOption}, Run: runRaw},
+		"class":        {RequiredArguments: 1, FinalArgumentWhitespace: true, HasContent: true, Run: unsupported(true)},
+		"role":         {RequiredArguments: 1, FinalArgumentWhitespace: true, HasContent: true, Options: roleOptions, Run: runRoleDirective},
+		"default-role": {OptionalArguments: 1, Run: runDefaultRoleDirective},
+		"title":        {RequiredArguments: 1, FinalArgumentWhitespace: true, Run: unsupported(false)},
+	}
+}
+
+// processDirective parses a directive block into its arguments, options and
+// content as the registered directive specifies, and runs the directive to
+// build its nodes. An unknown directive, or a block that does not fit its
+// directive, is reported and kept as a literal block.
+func (p *Parser) processDirective(r *lineReader) []nodes.Node {
+	start := r.pos
+	line := r.next()
+	marker := p.patterns.directive.FindStringSubmatchIndex(line.text)
+	result, _ := p.runDirectiveBlock(r, start, line, line.text[marker[2]:marker[3]], marker[1], nil)
+	return result
+}
+
+// runDirectiveBlock reads the block of the directive named name, whose
+// marker ends at index end of line, and runs the directive. The block began
+// at line start of r. Inside a substitution definition, definition is the
+// substitution being defined. The boolean is false if the directive failed
+// and the block is returned as a literal block.
+func (p *Parser) runDirectiveBlock(r *lineReader, start int, line sourceLine, name string, end int, definition *substitution) ([]nodes.Node, bool) {
+	first := line.slice(end)
+	first = first.slice(len(first.text) - len(strings.TrimLeft(first.text, " ")))
+	body, indent := r.firstKnownIndented(first)
+	p.checkExplicitFinish(r)
+	literal := []nodes.Node{nodes.NewCodeNode("", joinLines(r.lines[start:r.pos]), false)}
+
+	directive, ok := p.directives.Lookup(name)
+	if !ok {
+		p.report(LevelError, line.position(0), "Unknown directive type %q.", name)
+		return literal, false
+	}
+	ctx := &DirectiveContext{Name: name, Position: line.position(0), parser: p, line: line, indent: indent, definition: definition}
+	if definition != nil {
+		ctx.Substitution = definition.name
+	}
+	var message string
+	if ctx.Arguments, ctx.Options, ctx.content, message = p.parseDirectiveBlock(directive, body); message != "" {
+		p.report(LevelError, ctx.Position, "Error in %q directive:\n%s.", name, message)
+		return literal, false
+	}
+	ctx.content = trimBlankLines(ctx.content)
+
+	run := directive.Run
+	if run == nil {
+		run = runDirective
+	}
+	result, err := run(ctx)
+	if err != nil {
+		p.report(LevelError, ctx.Position, "%s", err)
+		return literal, false
+	}
+	return result, true
+}
+
+// runDirective builds a directive node holding the arguments, options and
+// raw content of the directive, with the content parsed into its children.
+func runDirective(ctx *DirectiveContext) ([]nodes.Node, error) {
+	directive := runLiteralNode(ctx)
+	for _, child := range ctx.ParseContent() {
+		directive.AddChild(child)
+	}
+	return []nodes.Node{directive}, nil
+}
+
+// unsupported handles the standard directives that the parser recognizes
+// but does not implement, such as contents and math. It reports the
+// directive and keeps any content: parsed if it is reStructuredText, as for
+// class, or else as a literal block.
+func unsupported(parsed bool) DirectiveFunc {
+	return func(ctx *DirectiveContext) ([]nodes.Node, error) {
+		ctx.Report(LevelWarning, "The %q directive is not supported.", ctx.Name)
+		switch {
+		case len(ctx.content) == 0:
+			return []nodes.Node{}, nil
+		case parsed:
+			return ctx.ParseContent(), nil
+		}
+		return []nodes.Node{nodes.NewCodeNode("", ctx.Content(), false)}, nil
+	}
+}
+
+func runLiteralNode(ctx *DirectiveContext) *nodes.DirectiveNode {
+	directive := nodes.NewDirectiveNode(ctx.Name, ctx.Arguments)
+	directive.SetOptions(ctx.Options)
+	directive.SetRawContent(ctx.Content())
+	return directive
+}
+
+// runAdmonition builds an admonition. The specific admonitions are titled
+// after their kind; the generic "admonition" takes its title as argument.
+func runAdmonition(ctx *DirectiveContext) ([]nodes.Node, error) {
+	if err := ctx.RequireContent(); err != nil {
+		return nil, err
+	}
+	kind := strings.ToLower(ctx.Name)
+	title := string(unicode.ToUpper(rune(kind[0]))) + kind[1:]
+	if len(ctx.Arguments) > 0 {
+		title = strings.Join(strings.Fields(ctx.Arguments[0]), " ")
+	}
+	admonition := nodes.NewAdmonitionNode(kind, title)
+	for _, child := range ctx.ParseContent() {
+		admonition.AddChild(child)
+	}
+	return []nodes.Node{admonition}, nil
+}
+
+// runImage builds an image from the image directive.
+func runImage(ctx *DirectiveContext) ([]nodes.Node, error) {
+	return []nodes.Node{newImage(ctx)}, nil
+}
+
+func newImage(ctx *DirectiveContext) *nodes.ImageNode {
+	uri, _ := URI(ctx.Arguments[0])
+	alt := ctx.Options.Value("alt")
+	if !ctx.Options.Has("alt") && ctx.Substitution != "" {
+		// An image substitution is described by its name
+		alt = ctx.Substitution
+	}
+	image := nodes.NewImageNode(uri, alt)
+	image.SetSize(ctx.Options.Value("width"), ctx.Options.Value("height"))
+	image.SetAlign(ctx.Options.Value("align"))
+	image.SetTarget(ctx.Options.Value("target"))
+	return image
+}
+
+// runFigure builds a figure: a directive node holding the image followed by
+// the caption and legend parsed from the content.
+func runFigure(ctx *DirectiveContext) ([]nodes.Node, error) {
+	figure := runLiteralNode(ctx)
+	figure.AddChild(newImage(ctx))
+	for _, child := range ctx.ParseContent() {
+		figure.AddChild(child)
+	}
+	return []nodes.Node{figure}, nil
+}
 
-	directiveNode := currentNode.(*nodes.DirectiveNode)
-	p.context.buffer = append(p.context.buffer, line)
+// parseDirectiveBlock divides the lines of a directive block, starting with
+// the text after the "::" marker, as docutils does. The arguments and the
+// option field list run up to the first blank line and the content follows
+// it. A directive without arguments starts its content right away, after
+// any options. Option values are converted as the directive specifies. It
+// returns a message if the block does not fit the directive.
+func (p *Parser) parseDirectiveBlock(spec Directive, lines []sourceLine) ([]string, nodes.DirectiveOptions, []sourceLine, string) {
+	if len(lines) > 0 && lines[0].isBlank() {
+		lines = lines[1:]
+	}
+	end := 0
+	for end < len(lines) && !lines[end].isBlank() {
+		end++
+	}
+	argBlock := lines[:end]
+	content := lines[min(end+1, len(lines)):]
+
+	options := make(nodes.DirectiveOptions)
+	for i, line := range argBlock {
+		if spec.Options != nil && p.patterns.field.MatchString(line.text) {
+			var message string
+			if options, message = p.parseDirectiveOptions(spec.Options, argBlock[i:]); message != "" {
+				return nil, nil, nil, message
+			}
+			argBlock = argBlock[:i]
+			break
+		}
+	}
+	if len(argBlock) > 0 && spec.RequiredArguments+spec.OptionalArguments == 0 {
+		content = append(argBlock[:len(argBlock):len(argBlock)], lines[end:]...)
+		argBlock = nil
+	}
+
+	arguments, message := parseDirectiveArguments(spec, joinLines(argBlock))
+	if message != "" {
+		return nil, nil, nil, message
+	}
+	if !spec.HasContent && len(trimBlankLines(content)) > 0 {
+		return nil, nil, nil, "no content permitted"
+	}
+	return arguments, options, content, ""
+}
 
-	if strings.TrimSpace(line) == "" {
-		content := strings.Join(p.context.buffer, "\n")
-		directiveNode.SetRawContent(content)
-		p.nodes = append(p.nodes, directiveNode)
-		p.context.Reset()
-		return nil
+// parseDirectiveArguments splits the argument text of a directive at
+// whitespace and checks the number of arguments against the spec.
+func parseDirectiveArguments(spec Directive, text string) ([]string, string) {
+	arguments := strings.Fields(text)
+	maximum := spec.RequiredArguments + spec.OptionalArguments
+	if len(arguments) < spec.RequiredArguments {
+		return nil, fmt.Sprintf("%d argument(s) required, %d supplied", spec.RequiredArguments, len(arguments))
 	}
+	if len(arguments) > maximum {
+		if !spec.FinalArgumentWhitespace {
+			return nil, fmt.Sprintf("maximum %d argument(s) allowed, %d supplied", maximum, len(arguments))
+		}
+		// The last argument is the rest of the text, whitespace included
+		rest := strings.TrimSpace(text)
+		for i := 0; i < maximum-1; i++ {
+			rest = strings.TrimLeft(rest[len(arguments[i]):], " \t\n")
+		}
+		arguments = append(arguments[:maximum-1], rest)
+	}
+	return arguments, ""
+}
 
-	return currentNode
+// parseDirectiveOptions parses the option field list of a directive
+// (":width: 200px") and converts the values as the spec requires. An option
+// value may continue on indented lines.
+func (p *Parser) parseDirectiveOptions(spec OptionSpec, lines []sourceLine) (nodes.DirectiveOptions, string) {
+	options := make(nodes.DirectiveOptions)
+	names := make([]string, 0)
+	for _, line := range lines {
+		if matches := p.patterns.field.FindStringSubmatch(line.text); matches != nil {
+			name := strings.ToLower(matches[1])
+			if options.Has(name) {
+				return nil, fmt.Sprintf("duplicate option %q", name)
+			}
+			options[name] = strings.TrimSpace(matches[2])
+			names = append(names, name)
+			continue
+		}
+		if len(names) == 0 || line.indent() == 0 {
+			return nil, "invalid option block"
+		}
+		name := names[len(names)-1]
+		options[name] = strings.TrimSpace(options[name] + " " + strings.TrimSpace(line.text))
+	}
+
+	for _, name := range names {
+		convert, ok := spec[name]
+		if !ok {
+			return nil, fmt.Sprintf("unknown option: %q", name)
+		}
+		value, err := convert(options[name])
+		if err != nil {
+			return nil, fmt.Sprintf("invalid option value: (option: %q; value: %q)\n%v", name, options[name], err)
+		}
+		options[name] = value
+	}
+	return options, ""
 }
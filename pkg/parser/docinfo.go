@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// SetDocInfo enables or disables the docinfo transform, which turns a field
+// list at the start of the document, after any title and subtitle, into a
+// DocInfoNode. It is enabled by default.
+func (p *Parser) SetDocInfo(enabled bool) {
+	p.docInfo = enabled
+}
+
+// promoteDocInfo replaces the first field list of the document with a
+// DocInfoNode if nothing but the title, subtitle, comments and metadata
+// precede it.
+func (p *Parser) promoteDocInfo(body []nodes.Node) {
+	for i, node := range body {
+		switch list := node.(type) {
+		case *nodes.TitleNode, *nodes.SubtitleNode:
+			continue
+		case *nodes.FieldListNode:
+			body[i] = p.newDocInfo(list)
+			return
+		}
+		if !isPreBibliographic(node) {
+			return
+		}
+	}
+}
+
+// newDocInfo creates a DocInfoNode from the fields of list, extracting the
+// recognized bibliographic fields. Other fields are kept as they are.
+func (p *Parser) newDocInfo(list *nodes.FieldListNode) *nodes.DocInfoNode {
+	docInfo := nodes.NewDocInfoNode()
+	docInfo.SetPosition(list.Start(), list.End())
+	for _, child := range list.Children() {
+		docInfo.AddChild(child)
+		field, ok := child.(*nodes.FieldNode)
+		if !ok {
+			continue
+		}
+		name := strings.ToLower(field.Name())
+		switch name {
+		case "abstract":
+			docInfo.SetAbstract(field.Children())
+			continue
+		case "authors":
+			if authors, ok := fieldAuthors(field); ok {
+				for _, author := range authors {
+					docInfo.AddAuthor(author)
+				}
+				continue
+			}
+		case "author", "date", "version", "status", "copyright":
+			if text, ok := fieldText(field); ok {
+				switch name {
+				case "author":
+					docInfo.AddAuthor(text)
+				case "date":
+					docInfo.SetDate(text)
+				case "version":
+					docInfo.SetVersion(text)
+				case "status":
+					docInfo.SetStatus(text)
+				case "copyright":
+					docInfo.SetCopyright(text)
+				}
+				continue
+			}
+		default:
+			continue
+		}
+		p.report(LevelWarning, field.Start(),
+			"Cannot extract bibliographic field %q containing anything other than a single paragraph.", field.Name())
+	}
+	return docInfo
+}
+
+// fieldText returns the text of a field whose body is a single paragraph.
+func fieldText(field *nodes.FieldNode) (string, bool) {
+	children := field.Children()
+	if len(children) != 1 || children[0].Type() != nodes.NodeParagraph {
+		return "", false
+	}
+	return strings.Join(strings.Fields(nodes.TextContent(children[0])), " "), true
+}
+
+// fieldAuthors returns the authors listed in an authors field: a paragraph
+// of names separated by semicolons, or by commas if there are no semicolons,
+// or a bullet list with one name per item.
+func fieldAuthors(field *nodes.FieldNode) ([]string, bool) {
+	children := field.Children()
+	if len(children) != 1 {
+		return nil, false
+	}
+	var authors []string
+	switch body := children[0].(type) {
+	case *nodes.ParagraphNode:
+		text, _ := fieldText(field)
+		separator := ","
+		if strings.Contains(text, ";") {
+			separator = ";"
+		}
+		for _, author := range strings.Split(text, separator) {
+			if author = strings.TrimSpace(author); author != "" {
+				authors = append(authors, author)
+			}
+		}
+	case *nodes.ListNode:
+		for _, item := range body.Children() {
+			author := strings.Join(strings.Fields(nodes.TextContent(item)), " ")
+			if author != "" {
+				authors = append(authors, author)
+			}
+		}
+	default:
+		return nil, false
+	}
+	return authors, true
+}
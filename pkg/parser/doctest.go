@@ -1,14 +1,41 @@
 package parser
 
-import "strings"
-
-func processDoctestBlock(line string) Token {
-	// Handle doctest blocks
-	if strings.HasPrefix(line, ">>> ") {
-		return Token{
-			Type:    TokenDoctest,
-			Content: line[4:], // Remove the leading '>>> '
+import (
+	"strings"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// processDoctestBlock handles parsing of doctest blocks
+// A doctest block is an interactive Python session pasted into the text. It
+// starts with a ">>> " prompt and ends at the first blank line. Each prompt
+// begins a new example; the "... " lines directly after it continue the
+// command, and the lines up to the next prompt are its expected output.
+func (p *Parser) processDoctestBlock(r *lineReader) *nodes.DoctestNode {
+	node := nodes.NewDoctestNode()
+	var example *nodes.DoctestExample
+	var output []string
+	finish := func() {
+		if example != nil {
+			example.Expected = strings.Join(output, "\n")
+			node.AddExample(*example)
+		}
+	}
+
+	for !r.eof() && !r.peek().isBlank() {
+		text := strings.TrimRight(r.next().text, " \t")
+		if matches := p.patterns.doctest.FindStringSubmatch(text); matches != nil {
+			finish()
+			example = &nodes.DoctestExample{Command: matches[1]}
+			output = nil
+			continue
+		}
+		if matches := p.patterns.doctestContinue.FindStringSubmatch(text); matches != nil && len(output) == 0 {
+			example.Continuation = append(example.Continuation, matches[1])
+			continue
 		}
+		output = append(output, text)
 	}
-	return Token{}
+	finish()
+	return node
 }
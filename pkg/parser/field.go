@@ -0,0 +1,38 @@
+package parser
+
+import "github.com/go-i2p/go-rst/pkg/nodes"
+
+// processFieldList parses consecutive fields into a FieldListNode. The body
+// of a field starts after its name and continues with the indented lines
+// that follow; it is parsed as a nested block.
+func (p *Parser) processFieldList(r *lineReader) *nodes.FieldListNode {
+	list := nodes.NewFieldListNode()
+	for {
+		// Fields may be separated by blank lines.
+		end := r.pos
+		r.skipBlank()
+		if r.eof() {
+			break
+		}
+		line := r.peek()
+		token := p.lexer.Tokenize(line.text)
+		if line.indent() > 0 || token.Type != TokenField {
+			if r.pos == end {
+				p.checkBlankFinish(r, "Field list")
+			}
+			break
+		}
+		start := r.pos
+		r.next()
+
+		markerWidth := len(line.text) - len(token.Content)
+		body, _ := r.firstKnownIndented(line.slice(markerWidth))
+		field := nodes.NewFieldNode(unescape(token.Args[0]))
+		field.SetPosition(spanLines(r.lines[start:r.pos]))
+		for _, child := range p.parseNested(body, markerWidth) {
+			field.AddChild(child)
+		}
+		list.AppendChild(field)
+	}
+	return list
+}
@@ -0,0 +1,149 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// footnoteSymbols label symbol footnotes in turn. After the last one they
+// start over, doubled, then tripled and so on.
+var footnoteSymbols = []string{"*", "†", "‡", "§", "¶", "#", "♠", "♥", "♦", "♣"}
+
+// footnoteKind tells the kind of a footnote from its label as written, and
+// returns the name its references match.
+func footnoteKind(label string) (nodes.FootnoteKind, string) {
+	switch {
+	case label == "*":
+		return nodes.FootnoteSymbol, ""
+	case strings.HasPrefix(label, "#"):
+		return nodes.FootnoteNumbered, normalizeName(label[1:])
+	case strings.Trim(label, "0123456789") == "":
+		return nodes.FootnoteManual, label
+	default:
+		return nodes.FootnoteCitation, normalizeName(label)
+	}
+}
+
+// processFootnote parses a footnote or citation (".. [1] Text"). The body
+// starts after the label and continues on the indented lines that follow.
+func (p *Parser) processFootnote(r *lineReader) *nodes.FootnoteNode {
+	line := r.next()
+	marker := p.patterns.footnote.FindStringSubmatchIndex(line.text)
+	label := line.text[marker[2]:marker[3]]
+	body, indent := r.firstKnownIndented(line.slice(marker[1]))
+	p.checkExplicitFinish(r)
+
+	kind, name := footnoteKind(label)
+	footnote := nodes.NewFootnoteNode(kind, name, label)
+	for _, child := range p.parseNested(body, indent) {
+		footnote.AddChild(child)
+	}
+	return footnote
+}
+
+// resolveFootnotes labels the footnotes of the document and links their
+// references to them, and them back to their references, by the IDs given
+// by identify. Auto-numbered footnotes take the lowest numbers not used by
+// manually numbered ones, and symbol footnotes take the symbols in order.
+// References match footnotes of the same name, or the next anonymous
+// footnote of their kind.
+func (p *Parser) resolveFootnotes(doc []nodes.Node) {
+	var footnotes []*nodes.FootnoteNode
+	var refs []*nodes.FootnoteReferenceNode
+	walkNodes(doc, func(node nodes.Node) {
+		switch n := node.(type) {
+		case *nodes.FootnoteNode:
+			footnotes = append(footnotes, n)
+		case *nodes.FootnoteReferenceNode:
+			refs = append(refs, n)
+		}
+	})
+
+	named := make(map[string]*nodes.FootnoteNode)
+	used := make(map[string]bool)
+	for _, footnote := range footnotes {
+		if footnote.Kind() == nodes.FootnoteManual {
+			used[footnote.Label()] = true
+		}
+	}
+	var numbered, symbols []*nodes.FootnoteNode
+	number := 1
+	for _, footnote := range footnotes {
+		switch footnote.Kind() {
+		case nodes.FootnoteNumbered:
+			for used[strconv.Itoa(number)] {
+				number++
+			}
+			footnote.SetLabel(strconv.Itoa(number))
+			number++
+			if footnote.Name() == "" {
+				numbered = append(numbered, footnote)
+			}
+		case nodes.FootnoteSymbol:
+			footnote.SetLabel(footnoteSymbol(len(symbols)))
+			symbols = append(symbols, footnote)
+		}
+		if name := footnote.Name(); name != "" {
+			if _, ok := named[name]; ok {
+				p.report(LevelWarning, footnote.Start(), "Duplicate explicit target name: %q.", name)
+				continue
+			}
+			named[name] = footnote
+		}
+	}
+
+	for _, ref := range refs {
+		var footnote *nodes.FootnoteNode
+		switch {
+		case ref.Name() != "":
+			if footnote = named[ref.Name()]; footnote == nil {
+				p.report(LevelError, ref.Start(), "Unknown target name: %q.", ref.Name())
+			}
+		case ref.Kind() == nodes.FootnoteSymbol:
+			if len(symbols) == 0 {
+				p.report(LevelError, ref.Start(), "Too many symbol footnote references: only %d corresponding footnotes available.", countKind(footnotes, nodes.FootnoteSymbol))
+				break
+			}
+			footnote, symbols = symbols[0], symbols[1:]
+		default:
+			if len(numbered) == 0 {
+				p.report(LevelError, ref.Start(), "Too many autonumbered footnote references: only %d corresponding footnotes available.", countKind(footnotes, nodes.FootnoteNumbered))
+				break
+			}
+			footnote, numbered = numbered[0], numbered[1:]
+		}
+		if footnote == nil {
+			continue
+		}
+		ref.SetLabel(footnote.Label())
+		ref.SetContent(footnote.Label())
+		ref.SetRefID(footnote.ID())
+		footnote.AddBackref(ref.ID())
+	}
+}
+
+// footnoteSymbol returns the label of the symbol footnote at index i.
+func footnoteSymbol(i int) string {
+	return strings.Repeat(footnoteSymbols[i%len(footnoteSymbols)], i/len(footnoteSymbols)+1)
+}
+
+// countKind counts the anonymous footnotes of a kind.
+func countKind(footnotes []*nodes.FootnoteNode, kind nodes.FootnoteKind) int {
+	count := 0
+	for _, footnote := range footnotes {
+		if footnote.Kind() == kind && footnote.Name() == "" {
+			count++
+		}
+	}
+	return count
+}
+
+// walkNodes calls visit for every node of the tree in document order.
+func walkNodes(list []nodes.Node, visit func(nodes.Node)) {
+	for _, node := range list {
+		visit(node)
+		walkNodes(node.Children(), visit)
+	}
+}
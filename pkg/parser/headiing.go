@@ -2,20 +2,107 @@ package parser
 
 import (
 	"strings"
+	"unicode/utf8"
 
 	"github.com/go-i2p/go-rst/pkg/nodes"
 )
 
-func (p *Parser) processHeading(content, underline string) nodes.Node {
-	level := 1
-	switch underline[0] {
-	case '-':
-		level = 2
-	case '~':
-		level = 3
+// adornmentStyle identifies how a section title is adorned. A title with an
+// overline is a different style from one underlined with the same character.
+type adornmentStyle struct {
+	char     byte
+	overline bool
+}
+
+// processHeading parses a section title, either underlined or both overlined
+// and underlined. It returns nil, consuming nothing, if the current line does
+// not start a title. Sections are only recognized at the top level of the
+// document, never inside nested blocks.
+func (p *Parser) processHeading(r *lineReader) nodes.Node {
+	if p.patterns.adornment.MatchString(r.peek().text) {
+		return p.processOverlinedHeading(r)
+	}
+	return p.processUnderlinedHeading(r)
+}
+
+// processUnderlinedHeading parses a title line followed by its underline.
+func (p *Parser) processUnderlinedHeading(r *lineReader) nodes.Node {
+	line := r.peek()
+	title := strings.TrimSpace(line.text)
+	underline, ok := r.peekAt(1)
+	if !ok || !p.patterns.adornment.MatchString(underline.text) {
+		return nil
 	}
+	// Short underlines are tolerated, but very short ones are more likely
+	// to be ordinary text.
+	if len(underline.text) < utf8.RuneCountInString(title) && len(underline.text) < 4 {
+		return nil
+	}
+	if p.context.Depth() > 0 {
+		p.report(LevelSevere, line.position(0), "Unexpected section title.")
+		return nil
+	}
+	if len(underline.text) < utf8.RuneCountInString(title) {
+		p.report(LevelWarning, underline.position(0), "Title underline too short.")
+	}
+	r.next()
+	r.next()
+
+	return p.newHeading(title, line, adornmentStyle{char: underline.text[0]})
+}
 
-	node := nodes.NewHeadingNode(strings.TrimSpace(content), level)
-	p.nodes = append(p.nodes, node)
-	return nil
+// processOverlinedHeading parses a title between an overline and a matching
+// underline. The title itself may be inset. Adornments shorter than four
+// characters that do not form a title are left to be parsed as text.
+func (p *Parser) processOverlinedHeading(r *lineReader) nodes.Node {
+	overline := r.peek()
+	line, ok := r.peekAt(1)
+	if !ok || line.isBlank() {
+		return nil
+	}
+	short := len(overline.text) < 4
+	underline, ok := r.peekAt(2)
+	switch {
+	case !ok:
+		if !short {
+			p.report(LevelSevere, overline.position(0), "Incomplete section title.")
+		}
+		return nil
+	case !p.patterns.adornment.MatchString(underline.text):
+		if !short {
+			p.report(LevelSevere, overline.position(0), "Missing matching underline for section title overline.")
+		}
+		return nil
+	case underline.text != overline.text:
+		if !short {
+			p.report(LevelSevere, overline.position(0), "Title overline & underline mismatch.")
+		}
+		return nil
+	}
+
+	title := strings.TrimSpace(line.text)
+	if p.context.Depth() > 0 {
+		p.report(LevelSevere, overline.position(0), "Unexpected section title.")
+		return nil
+	}
+	if len(overline.text) < utf8.RuneCountInString(title) {
+		p.report(LevelWarning, overline.position(0), "Title overline too short.")
+	}
+	r.next()
+	r.next()
+	r.next()
+
+	return p.newHeading(title, line.slice(line.indent()), adornmentStyle{char: overline.text[0], overline: true})
+}
+
+// newHeading creates the heading for a title whose level follows from the
+// order in which adornment styles appear in the document.
+func (p *Parser) newHeading(title string, line sourceLine, style adornmentStyle) *nodes.HeadingNode {
+	level, ok := p.context.sectionLevel(style)
+	if !ok {
+		p.report(LevelSevere, line.position(0), "Title level inconsistent.")
+	}
+	heading := nodes.NewHeadingNode(title, level)
+	p.addInline(heading, title, []sourceLine{line})
+	return heading
 }
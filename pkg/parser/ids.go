@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// idFolds spells letters outside ASCII that commonly appear in names with
+// ASCII letters, so that "Café" and "Straße" keep their letters in IDs.
+var idFolds = strings.NewReplacer(
+	"à", "a", "á", "a", "â", "a", "ã", "a", "ä", "a", "å", "a", "ā", "a", "ă", "a", "ą", "a",
+	"ç", "c", "ć", "c", "č", "c", "ď", "d", "đ", "d",
+	"è", "e", "é", "e", "ê", "e", "ë", "e", "ē", "e", "ę", "e", "ě", "e",
+	"ğ", "g", "ħ", "h", "ì", "i", "í", "i", "î", "i", "ï", "i", "ī", "i", "ı", "i",
+	"ł", "l", "ľ", "l", "ñ", "n", "ń", "n", "ň", "n",
+	"ò", "o", "ó", "o", "ô", "o", "õ", "o", "ö", "o", "ø", "o", "ō", "o", "ő", "o",
+	"ř", "r", "ś", "s", "š", "s", "ş", "s", "ť", "t", "ţ", "t",
+	"ù", "u", "ú", "u", "û", "u", "ü", "u", "ū", "u", "ů", "u", "ű", "u",
+	"ý", "y", "ÿ", "y", "ź", "z", "ż", "z", "ž", "z",
+	"ß", "sz", "æ", "ae", "œ", "oe", "þ", "th", "ð", "d",
+)
+
+// makeID converts a name to an identifier as docutils does: lowercased,
+// with characters outside ASCII folded or dropped, runs of other characters
+// replaced by single hyphens, and leading digits and hyphens removed. The
+// result may be empty.
+func makeID(name string) string {
+	id := idFolds.Replace(strings.ToLower(name))
+	var b strings.Builder
+	hyphen := false
+	for _, c := range id {
+		if c >= 'a' && c <= 'z' || c >= '0' && c <= '9' {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(c)
+			hyphen = false
+			continue
+		}
+		// Letters outside ASCII that were not folded are dropped
+		if c < utf8.RuneSelf || unicode.IsSpace(c) {
+			hyphen = true
+		}
+	}
+	return strings.TrimLeft(b.String(), "0123456789-")
+}
+
+// setID gives node a unique ID made from name. If the ID is taken, a number
+// is added to it; if name makes no ID, the node is numbered after kind, as
+// in "footnote-1".
+func (p *Parser) setID(node nodes.Node, name, kind string) {
+	base := makeID(name)
+	id := base
+	for n := 1; id == "" || p.ids[id] != nil; n++ {
+		if base == "" {
+			id = fmt.Sprintf("%s-%d", kind, n)
+		} else {
+			id = fmt.Sprintf("%s-%d", base, n)
+		}
+	}
+	node.SetID(id)
+	p.ids[id] = node
+}
+
+// identify gives IDs to the elements of the document that links may point
+// to: sections and the document title, named hyperlink targets, footnotes,
+// citations and the references to them. IDs are made from the element's
+// name and kept unique in document order.
+func (p *Parser) identify(doc []nodes.Node) {
+	walkNodes(doc, func(node nodes.Node) {
+		switch n := node.(type) {
+		case *nodes.SectionNode:
+			if heading := n.Heading(); heading != nil {
+				p.setID(n, nodes.TextContent(heading), "section")
+			}
+		case *nodes.TitleNode, *nodes.SubtitleNode:
+			p.setID(n, nodes.TextContent(n), "section")
+		case *nodes.TargetNode:
+			if !n.IsAnonymous() {
+				p.setID(n, n.Name(), "target")
+			}
+		case *nodes.FootnoteNode:
+			if n.IsCitation() {
+				p.setID(n, n.Name(), "citation")
+			} else {
+				p.setID(n, n.Name(), "footnote")
+			}
+		case *nodes.FootnoteReferenceNode:
+			if n.IsCitation() {
+				p.setID(n, "", "citation-reference")
+			} else {
+				p.setID(n, "", "footnote-reference")
+			}
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// runInclude inserts the contents of a file into the document. The text is
+// parsed as reStructuredText in place of the directive, or with the
+// "literal" or "code" option kept as a literal block. Options select part
+// of the file by line numbers or by the text around it. Paths in the
+// included file are relative to its own directory.
+func runInclude(ctx *DirectiveContext) ([]nodes.Node, error) {
+	p := ctx.parser
+	if !p.fileInsertion {
+		ctx.Report(LevelWarning, "%q directive disabled.", ctx.Name)
+		return []nodes.Node{}, nil
+	}
+	name := ctx.Arguments[0]
+	if strings.HasPrefix(name, "<") && strings.HasSuffix(name, ">") {
+		return nil, ctx.Errorf("standard include files are not supported: %s", name)
+	}
+	path := filepath.Clean(p.sourceRelative(name))
+	if p.including[path] {
+		return nil, fmt.Errorf("Circular inclusion in %q directive: %s.", ctx.Name, name)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Problems with %q directive path:\n%v.", ctx.Name, err)
+	}
+	text, err := decodeText(raw, ctx.Options.Value("encoding"))
+	if err != nil {
+		return nil, fmt.Errorf("Problems with %q directive path:\n%v.", ctx.Name, err)
+	}
+	if text, err = includedPart(ctx, text); err != nil {
+		return nil, err
+	}
+
+	if ctx.Options.Has("literal") || ctx.Options.Has("code") {
+		if width, ok := ctx.Options["tab-width"]; ok {
+			n, _ := strconv.Atoi(width)
+			text = expandTabs(text, n)
+		}
+		text = strings.Trim(text, "\n")
+		lineNumbers := ctx.Options.Has("number-lines")
+		return []nodes.Node{nodes.NewCodeNode(ctx.Options.Value("code"), text, lineNumbers)}, nil
+	}
+	if parser := strings.ToLower(ctx.Options.Value("parser")); parser != "" && parser != "rst" && parser != "restructuredtext" {
+		return nil, ctx.Errorf("parser %q is not supported", ctx.Options.Value("parser"))
+	}
+
+	p.including[path] = true
+	defer delete(p.including, path)
+	defer func(source string) { p.sourcePath = source }(p.sourcePath)
+	p.sourcePath = path
+	return p.parseBlocks(splitLines(text)), nil
+}
+
+// includedPart returns the part of text selected by the options of an
+// include directive: the lines from "start-line" up to "end-line", counted
+// from zero and from the end if negative, then the text after "start-after"
+// and before "end-before".
+func includedPart(ctx *DirectiveContext, text string) (string, error) {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	start, end := 0, len(lines)
+	if value, ok := ctx.Options["start-line"]; ok {
+		n, _ := strconv.Atoi(value)
+		start = lineIndex(n, len(lines))
+	}
+	if value, ok := ctx.Options["end-line"]; ok {
+		n, _ := strconv.Atoi(value)
+		end = lineIndex(n, len(lines))
+	}
+	text = strings.Join(lines[start:max(start, end)], "")
+
+	if after, ok := ctx.Options["start-after"]; ok {
+		i := strings.Index(text, after)
+		if i < 0 {
+			return "", fmt.Errorf("Problem with \"start-after\" option of %q directive:\nText not found.", ctx.Name)
+		}
+		text = text[i+len(after):]
+	}
+	if before, ok := ctx.Options["end-before"]; ok {
+		i := strings.Index(text, before)
+		if i < 0 {
+			return "", fmt.Errorf("Problem with \"end-before\" option of %q directive:\nText not found.", ctx.Name)
+		}
+		text = text[:i]
+	}
+	return text, nil
+}
+
+// lineIndex converts a line number as Python slices take it, counted from
+// the end if negative, to an index into count lines.
+func lineIndex(n, count int) int {
+	if n < 0 {
+		n += count
+	}
+	return min(max(n, 0), count)
+}
+
+// expandTabs replaces the tabs in text with spaces up to the next multiple
+// of width columns.
+func expandTabs(text string, width int) string {
+	if width <= 0 || !strings.Contains(text, "\t") {
+		return text
+	}
+	var b strings.Builder
+	column := 0
+	for _, c := range text {
+		switch c {
+		case '\t':
+			n := width - column%width
+			b.WriteString(strings.Repeat(" ", n))
+			column += n
+		case '\n':
+			b.WriteRune(c)
+			column = 0
+		default:
+			b.WriteRune(c)
+			column++
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,435 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// inlineParser splits the text of a paragraph, heading or other text element
+// into inline nodes following the docutils inline markup recognition rules.
+type inlineParser struct {
+	p     *Parser
+	text  string
+	lines []sourceLine // source lines of text, used to locate inline nodes
+	nodes []nodes.Node
+	start int // start of the plain text not yet emitted
+}
+
+// parseInline parses inline markup in text and returns the resulting nodes.
+// The text is the newline-joined text of lines, which give the source
+// positions of the nodes. Without lines the nodes are left unpositioned.
+func (p *Parser) parseInline(text string, lines []sourceLine) []nodes.Node {
+	ip := &inlineParser{
+		p:     p,
+		text:  text,
+		lines: lines,
+		nodes: make([]nodes.Node, 0),
+	}
+	ip.parse()
+	return ip.nodes
+}
+
+// addInline parses text and attaches the resulting inline nodes to node.
+func (p *Parser) addInline(node nodes.Node, text string, lines []sourceLine) {
+	for _, child := range p.parseInline(text, lines) {
+		node.AddChild(child)
+	}
+}
+
+func (ip *inlineParser) parse() {
+	for i := 0; i < len(ip.text); {
+		if ip.text[i] == '\\' {
+			// Skip the backslash and the character it escapes
+			i++
+			if i < len(ip.text) {
+				_, size := utf8.DecodeRuneInString(ip.text[i:])
+				i += size
+			}
+			continue
+		}
+		if end := ip.parseMarkup(i); end > i {
+			i = end
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(ip.text[i:])
+		i += size
+	}
+	ip.flushText(len(ip.text))
+}
+
+// parseMarkup tries to recognize inline markup starting at i. It returns the
+// end of the markup, or i if there is none.
+func (ip *inlineParser) parseMarkup(i int) int {
+	if !ip.startAllowed(i) {
+		return i
+	}
+	rest := ip.text[i:]
+	switch {
+	case strings.HasPrefix(rest, "``"):
+		return ip.parseLiteral(i)
+	case strings.HasPrefix(rest, "**"):
+		return ip.parseStrong(i)
+	case rest[0] == '*':
+		return ip.parseEmphasis(i)
+	case rest[0] == '`':
+		return ip.parseInterpreted(i, i, "")
+	case rest[0] == '|':
+		return ip.parseSubstitution(i)
+	case rest[0] == '[':
+		return ip.parseFootnoteReference(i)
+	case rest[0] == ':':
+		if matches := ip.p.patterns.rolePrefix.FindStringSubmatch(rest); matches != nil {
+			return ip.parseInterpreted(i, i+len(matches[0])-1, matches[1])
+		}
+		return i
+	}
+	if end := ip.parseStandaloneURI(i); end > i {
+		return end
+	}
+	return ip.parseSimpleReference(i)
+}
+
+func (ip *inlineParser) parseLiteral(i int) int {
+	end := ip.findEnd(i, i+2, "``", endLiteral, nil)
+	if end < 0 {
+		ip.unterminated(i, i+2, "literal")
+		return i
+	}
+	ip.emit(i, end+2, nodes.NewLiteralNode(ip.text[i+2:end]))
+	return end + 2
+}
+
+func (ip *inlineParser) parseStrong(i int) int {
+	end := ip.findEnd(i, i+2, "**", endPlain, nil)
+	if end < 0 {
+		ip.unterminated(i, i+2, "strong")
+		return i
+	}
+	ip.emit(i, end+2, ip.p.processStrong(unescape(ip.text[i+2:end])))
+	return end + 2
+}
+
+func (ip *inlineParser) parseEmphasis(i int) int {
+	if strings.HasPrefix(ip.text[i+1:], "*") {
+		return i
+	}
+	end := ip.findEnd(i, i+1, "*", endPlain, nil)
+	if end < 0 {
+		ip.unterminated(i, i+1, "emphasis")
+		return i
+	}
+	ip.emit(i, end+1, ip.p.processEmphasis(unescape(ip.text[i+1:end])))
+	return end + 1
+}
+
+// parseInterpreted parses interpreted text or a phrase reference whose opening
+// backtick is at tick. Markup starts at i, which precedes tick when the text
+// has a role prefix.
+func (ip *inlineParser) parseInterpreted(i, tick int, role string) int {
+	suffix := ""
+	end := ip.findEnd(i, tick+1, "`", endBacktick, func(after int) int {
+		rest := ip.text[after:]
+		switch {
+		case role != "":
+			suffix = ""
+		case strings.HasPrefix(rest, "__"):
+			suffix = "__"
+		case strings.HasPrefix(rest, "_"):
+			suffix = "_"
+		default:
+			suffix = ip.p.patterns.roleSuffix.FindString(rest)
+		}
+		return len(suffix)
+	})
+	if end < 0 {
+		ip.unterminated(i, tick+1, "interpreted text or phrase reference")
+		return i
+	}
+	raw := ip.text[tick+1 : end]
+	stop := end + 1 + len(suffix)
+
+	switch suffix {
+	case "_", "__":
+		ip.emit(i, stop, ip.p.processReference(raw, suffix == "__"))
+	default:
+		if suffix != "" {
+			role = suffix[1 : len(suffix)-1]
+		}
+		var pos nodes.Position
+		if len(ip.lines) > 0 {
+			pos = ip.position(i)
+		}
+		ip.flushText(i)
+		for _, node := range ip.p.interpret(role, unescape(raw), ip.text[i:stop], pos) {
+			ip.emit(i, stop, node)
+		}
+		ip.start = stop
+	}
+	return stop
+}
+
+func (ip *inlineParser) parseSubstitution(i int) int {
+	if strings.HasPrefix(ip.text[i+1:], "|") {
+		return i
+	}
+	suffix := ""
+	end := ip.findEnd(i, i+1, "|", endPlain, func(after int) int {
+		suffix = ""
+		if strings.HasPrefix(ip.text[after:], "__") {
+			suffix = "__"
+		} else if strings.HasPrefix(ip.text[after:], "_") {
+			suffix = "_"
+		}
+		return len(suffix)
+	})
+	if end < 0 {
+		ip.unterminated(i, i+1, "substitution_reference")
+		return i
+	}
+	name := strings.Join(strings.Fields(unescape(ip.text[i+1:end])), " ")
+	stop := end + 1 + len(suffix)
+
+	var node nodes.Node = nodes.NewSubstitutionReferenceNode(name)
+	if suffix != "" {
+		link := nodes.NewLinkNode(name, "", "")
+		link.SetRefName(normalizeName(name))
+		link.SetAnonymous(suffix == "__")
+		link.AddChild(node)
+		node = link
+	}
+	ip.emit(i, stop, node)
+	return stop
+}
+
+// parseFootnoteReference parses a footnote or citation reference such as
+// [1]_ or [CIT2002]_.
+func (ip *inlineParser) parseFootnoteReference(i int) int {
+	matches := ip.p.patterns.footnoteReference.FindStringSubmatch(ip.text[i:])
+	if matches == nil || !ip.endAllowed(i+len(matches[0])) {
+		return i
+	}
+	stop := i + len(matches[0])
+	kind, name := footnoteKind(matches[1])
+	ip.emit(i, stop, nodes.NewFootnoteReferenceNode(kind, name, matches[1]))
+	return stop
+}
+
+// parseStandaloneURI links a URI or email address written in running text.
+// Trailing punctuation is left out of the link.
+func (ip *inlineParser) parseStandaloneURI(i int) int {
+	rest := ip.text[i:]
+	text := ip.p.patterns.standaloneURI.FindString(rest)
+	uri := text
+	if text == "" {
+		text = ip.p.patterns.email.FindString(rest)
+		uri = "mailto:" + text
+	}
+	if text == "" || !ip.endAllowed(i+len(text)) {
+		return i
+	}
+	stop := i + len(text)
+	ip.emit(i, stop, nodes.NewLinkNode(text, uri, ""))
+	return stop
+}
+
+// parseSimpleReference parses a one-word hyperlink reference such as name_.
+func (ip *inlineParser) parseSimpleReference(i int) int {
+	matches := ip.p.patterns.simpleReference.FindStringSubmatch(ip.text[i:])
+	if matches == nil || !ip.endAllowed(i+len(matches[0])) {
+		return i
+	}
+	stop := i + len(matches[0])
+	link := nodes.NewLinkNode(matches[1], "", "")
+	link.SetRefName(normalizeName(matches[1]))
+	link.SetAnonymous(matches[2] == "__")
+	ip.emit(i, stop, link)
+	return stop
+}
+
+// endRule tells what may come just before the end-string of inline markup,
+// following docutils.
+type endRule int
+
+const (
+	endPlain    endRule = iota // endPlain allows neither whitespace nor an escape
+	endLiteral                 // endLiteral allows no whitespace; backslashes are literal text
+	endBacktick                // endBacktick allows whitespace only if it is escaped, as in "\ `"
+)
+
+// findEnd returns the index of the end-string closing the markup that starts
+// at start and whose content starts at from, or -1 if there is none. The
+// rule tells what may precede the end-string. The suffix function, if
+// given, returns the length of any suffix following the end-string.
+func (ip *inlineParser) findEnd(start, from int, endString string, rule endRule, suffix func(after int) int) int {
+	if !ip.validStart(start, from) {
+		return -1
+	}
+	for j := from + 1; j < len(ip.text); {
+		offset := strings.Index(ip.text[j:], endString)
+		if offset < 0 {
+			return -1
+		}
+		j += offset
+		after := j + len(endString)
+		prev, size := utf8.DecodeLastRuneInString(ip.text[:j])
+		space := unicode.IsSpace(prev) && (rule != endBacktick || !isEscaped(ip.text, j-size))
+		if !space && (rule == endLiteral || !isEscaped(ip.text, j)) {
+			if suffix != nil {
+				after += suffix(after)
+			}
+			if ip.endAllowed(after) {
+				return j
+			}
+		}
+		j++
+	}
+	return -1
+}
+
+// validStart reports whether the start-string spanning [start, from) is
+// followed by content that may begin inline markup.
+func (ip *inlineParser) validStart(start, from int) bool {
+	return from < len(ip.text) && !isSpace(ip.text[from:]) && !ip.quoted(start, from)
+}
+
+// unterminated warns about a start-string spanning [start, from) that has no
+// matching end-string.
+func (ip *inlineParser) unterminated(start, from int, kind string) {
+	if !ip.validStart(start, from) {
+		return
+	}
+	var pos nodes.Position
+	if len(ip.lines) > 0 {
+		pos = ip.position(start)
+	}
+	ip.p.report(LevelWarning, pos, "Inline %s start-string without end-string.", kind)
+}
+
+// startAllowed reports whether inline markup may start at i: at the start of
+// the text, or after whitespace or opening and delimiting punctuation.
+func (ip *inlineParser) startAllowed(i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, _ := utf8.DecodeLastRuneInString(ip.text[:i])
+	if unicode.IsSpace(prev) || strings.ContainsRune(`'"([{<-/:`, prev) {
+		return true
+	}
+	return unicode.In(prev, unicode.Pd, unicode.Po, unicode.Ps, unicode.Pi, unicode.Pf)
+}
+
+// endAllowed reports whether inline markup may end just before i: at the end
+// of the text, or before whitespace or closing and delimiting punctuation.
+func (ip *inlineParser) endAllowed(i int) bool {
+	if i >= len(ip.text) {
+		return true
+	}
+	next, _ := utf8.DecodeRuneInString(ip.text[i:])
+	if unicode.IsSpace(next) || strings.ContainsRune(`'")]}>-/:.,;!?\`, next) {
+		return true
+	}
+	return unicode.In(next, unicode.Pd, unicode.Po, unicode.Pe, unicode.Pi, unicode.Pf)
+}
+
+// quoted reports whether the start-string spanning [start, from) is enclosed
+// in a pair of quotes or brackets, as in "*" or (*), in which case it is not
+// markup.
+func (ip *inlineParser) quoted(start, from int) bool {
+	if start == 0 || from >= len(ip.text) {
+		return false
+	}
+	closers := map[byte]byte{'\'': '\'', '"': '"', '(': ')', '[': ']', '{': '}', '<': '>'}
+	closer, ok := closers[ip.text[start-1]]
+	return ok && ip.text[from] == closer
+}
+
+// emit adds node for the markup spanning [start, end), preceded by any
+// pending plain text.
+func (ip *inlineParser) emit(start, end int, node nodes.Node) {
+	ip.flushText(start)
+	ip.locate(node, start, end)
+	ip.nodes = append(ip.nodes, node)
+	ip.start = end
+}
+
+// flushText emits the plain text between the last markup and end.
+func (ip *inlineParser) flushText(end int) {
+	if end <= ip.start {
+		return
+	}
+	if text := unescape(ip.text[ip.start:end]); text != "" {
+		node := nodes.NewTextNode(text)
+		ip.locate(node, ip.start, end)
+		ip.nodes = append(ip.nodes, node)
+	}
+	ip.start = end
+}
+
+// locate sets the source span of node, and of any children created with it,
+// to the text between start and end.
+func (ip *inlineParser) locate(node nodes.Node, start, end int) {
+	if len(ip.lines) == 0 {
+		return
+	}
+	startPos, endPos := ip.position(start), ip.position(end)
+	node.SetPosition(startPos, endPos)
+	for _, child := range node.Children() {
+		child.SetPosition(startPos, endPos)
+	}
+}
+
+// position maps a byte index in the text to its source position.
+func (ip *inlineParser) position(i int) nodes.Position {
+	for _, line := range ip.lines {
+		if i <= len(line.text) {
+			return line.position(i)
+		}
+		// Skip the line and the newline joining it to the next
+		i -= len(line.text) + 1
+	}
+	last := ip.lines[len(ip.lines)-1]
+	return last.position(len(last.text))
+}
+
+// isEscaped reports whether the character at i is preceded by an odd number
+// of backslashes.
+func isEscaped(text string, i int) bool {
+	count := 0
+	for j := i - 1; j >= 0 && text[j] == '\\'; j-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+// isSpace reports whether text starts with a whitespace character.
+func isSpace(text string) bool {
+	r, _ := utf8.DecodeRuneInString(text)
+	return unicode.IsSpace(r)
+}
+
+// unescape removes backslash escapes. Escaped whitespace is removed entirely.
+func unescape(text string) string {
+	if !strings.Contains(text, "\\") {
+		return text
+	}
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\\' {
+			b.WriteByte(text[i])
+			continue
+		}
+		i++
+		if i >= len(text) {
+			break
+		}
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !unicode.IsSpace(r) {
+			b.WriteRune(r)
+		}
+		i += size - 1
+	}
+	return b.String()
+}
@@ -27,6 +27,14 @@ const (
 	TokenTransition                        // TokenTransition represents a transition token.
 	TokenEmphasis                          // TokenEmphasis represents emphasized (italic) text
 	TokenStrong                            // TokenStrong represents strong (bold) text
+	TokenField                             // TokenField represents a field list item token.
+	TokenOptionList                        // TokenOptionList represents an option list item token.
+	TokenGridTable                         // TokenGridTable represents the top border of a grid table.
+	TokenSimpleTable                       // TokenSimpleTable represents the top border of a simple table.
+	TokenSubstitutionDef                   // TokenSubstitutionDef represents a substitution definition token.
+	TokenFootnote                          // TokenFootnote represents a footnote or citation token.
+	TokenTarget                            // TokenTarget represents a hyperlink target token.
+	TokenAnonymousTarget                   // TokenAnonymousTarget represents a short anonymous hyperlink target token.
 )
 
 // Token represents a single token in the input text.
@@ -69,14 +77,6 @@ func (l *Lexer) Tokenize(line string) Token {
 
 	line = strings.TrimLeft(line, " \t")
 
-	// Check for heading underline
-	if l.patterns.headingUnderline.MatchString(line) {
-		return Token{
-			Type:    TokenHeadingUnderline,
-			Content: line,
-		}
-	}
-
 	// Check for translation blocks
 	if matches := l.patterns.transBlock.FindStringSubmatch(line); len(matches) > 1 {
 		return Token{
@@ -124,6 +124,30 @@ func (l *Lexer) Tokenize(line string) Token {
 		}
 	}
 
+	// Check for footnote or citation
+	if matches := l.patterns.footnote.FindStringSubmatch(line); len(matches) > 1 {
+		return Token{
+			Type:    TokenFootnote,
+			Content: matches[1],
+		}
+	}
+
+	// Check for hyperlink targets
+	if l.patterns.target.MatchString(line) {
+		return Token{Type: TokenTarget}
+	}
+	if l.patterns.anonymousTarget.MatchString(line) {
+		return Token{Type: TokenAnonymousTarget}
+	}
+
+	// Check for substitution definition
+	if matches := l.patterns.substitutionDef.FindStringSubmatch(line); len(matches) > 1 {
+		return Token{
+			Type:    TokenSubstitutionDef,
+			Content: matches[1],
+		}
+	}
+
 	// Check for comment
 	if matches := l.patterns.comment.FindStringSubmatch(line); len(matches) > 1 {
 		return Token{
@@ -131,6 +155,15 @@ func (l *Lexer) Tokenize(line string) Token {
 			Content: matches[1],
 		}
 	}
+	// Check for field list
+	if matches := l.patterns.field.FindStringSubmatch(line); len(matches) > 1 {
+		return Token{
+			Type:    TokenField,
+			Content: matches[2],
+			Args:    []string{matches[1]}, // field name
+		}
+	}
+
 	// Check for bullet list
 	if matches := l.patterns.bulletList.FindStringSubmatch(line); len(matches) > 1 {
 		return Token{
@@ -144,24 +177,41 @@ func (l *Lexer) Tokenize(line string) Token {
 	if matches := l.patterns.enumList.FindStringSubmatch(line); len(matches) > 1 {
 		return Token{
 			Type:    TokenEnumList,
-			Content: matches[4],
-			Args:    []string{matches[1], matches[2]}, // indent, marker
+			Content: matches[5],
+			Args:    []string{matches[1], matches[3], matches[2], matches[4]}, // indent, enumerator, prefix, suffix
+		}
+	}
+
+	// Check for grid table
+	if l.patterns.gridTableTop.MatchString(line) {
+		return Token{
+			Type:    TokenGridTable,
+			Content: line,
+		}
+	}
+
+	// Check for simple table
+	if l.patterns.simpleTableTop.MatchString(line) {
+		return Token{
+			Type:    TokenSimpleTable,
+			Content: line,
 		}
 	}
 
-	// Check for strong (bold text) - must come before emphasis to avoid conflict
-	if matches := l.patterns.strong.FindStringSubmatch(line); len(matches) > 1 {
+	// Check for option list
+	if matches := l.patterns.optionMarker.FindStringSubmatch(line); len(matches) > 1 {
 		return Token{
-			Type:    TokenStrong,
-			Content: matches[1], // The text between double asterisks
+			Type:    TokenOptionList,
+			Content: matches[len(matches)-1],
+			Args:    []string{matches[1]}, // option group
 		}
 	}
 
-	// Check for emphasis (italic text)
-	if matches := l.patterns.emphasis.FindStringSubmatch(line); len(matches) > 1 {
+	// Check for doctest block (interactive Python session)
+	if matches := l.patterns.doctest.FindStringSubmatch(line); len(matches) > 0 {
 		return Token{
-			Type:    TokenEmphasis,
-			Content: matches[1], // The text between asterisks
+			Type:    TokenDoctest,
+			Content: matches[1], // The command after the >>> prompt
 		}
 	}
 
@@ -182,6 +232,14 @@ func (l *Lexer) Tokenize(line string) Token {
 		}
 	}
 
+	// Check for section title adornments too short to be transitions
+	if l.patterns.adornment.MatchString(line) {
+		return Token{
+			Type:    TokenHeadingUnderline,
+			Content: line,
+		}
+	}
+
 	// Regular text
 	return Token{
 		Type:    TokenText,
@@ -6,17 +6,27 @@ import (
 	"github.com/go-i2p/go-rst/pkg/nodes"
 )
 
-// processLineBlock handles parsing of line block tokens
-// Line blocks are used for poetry-style content where line breaks are preserved
-func (p *Parser) processLineBlock(content string, currentNode nodes.Node) nodes.Node {
-	// If we already have a line block node, append the line
-	if currentNode != nil && currentNode.Type() == nodes.NodeLineBlock {
-		lineBlockNode := currentNode.(*nodes.LineBlockNode)
-		currentLines := lineBlockNode.Lines()
-		newLines := append(currentLines, strings.TrimSpace(content))
-		return nodes.NewLineBlockNode(newLines)
+// processLineBlock handles parsing of line blocks
+// Line blocks are used for poetry-style content where line breaks are preserved.
+// An indented line continues the previous line.
+func (p *Parser) processLineBlock(r *lineReader) *nodes.LineBlockNode {
+	lines := make([]string, 0)
+	for !r.eof() {
+		line := r.peek()
+		if line.isBlank() {
+			break
+		}
+		if line.indent() > 0 && len(lines) > 0 {
+			lines[len(lines)-1] += " " + strings.TrimSpace(line.text)
+			r.next()
+			continue
+		}
+		matches := p.patterns.lineBlock.FindStringSubmatch(line.text)
+		if matches == nil {
+			break
+		}
+		r.next()
+		lines = append(lines, strings.TrimSpace(matches[1]))
 	}
-
-	// Otherwise create a new line block node
-	return nodes.NewLineBlockNode([]string{strings.TrimSpace(content)})
+	return nodes.NewLineBlockNode(lines)
 }
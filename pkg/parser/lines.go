@@ -0,0 +1,293 @@
+package parser
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// tabWidth is the tab stop interval used when expanding leading tabs, matching docutils.
+const tabWidth = 8
+
+// sourceLine is a single line of input with the indentation of its enclosing
+// block already removed.
+type sourceLine struct {
+	text   string // line text, right-trimmed, relative to the enclosing block
+	number int    // 1-based line number in the source
+	column int    // 1-based column of the first character of text
+	offset int    // byte offset of the first character of text in the source
+	pad    int    // bytes added to the start of text by tab expansion
+}
+
+// byteOrderMark is the UTF-8 encoded byte order mark that some editors write
+// at the start of a file.
+const byteOrderMark = "\uFEFF"
+
+// splitLines breaks content into source lines, expanding leading tabs and
+// removing trailing whitespace as docutils does.
+func splitLines(content string) []sourceLine {
+	// Reading from a strings.Reader never fails
+	lines, _ := readLines(strings.NewReader(content))
+	return lines
+}
+
+// readLines reads source lines from rd like splitLines. Lines may be of any
+// length. A byte order mark at the start of the input is skipped, and CRLF
+// line endings are treated like LF. On a read error, the lines read so far are
+// returned with the error.
+func readLines(rd io.Reader) ([]sourceLine, error) {
+	br := bufio.NewReader(rd)
+	lines := make([]sourceLine, 0)
+	offset := 0
+	for number := 1; ; number++ {
+		raw, err := br.ReadString('\n')
+		if raw != "" {
+			text, start := raw, offset
+			if number == 1 && strings.HasPrefix(text, byteOrderMark) {
+				text = text[len(byteOrderMark):]
+				start += len(byteOrderMark)
+			}
+			lines = append(lines, newSourceLine(strings.TrimRight(text, " \t\r\n"), number, start))
+			offset += len(raw)
+		}
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return lines, err
+		}
+	}
+}
+
+// newSourceLine creates a sourceLine for a raw line starting at offset.
+func newSourceLine(raw string, number, offset int) sourceLine {
+	text := expandIndent(raw)
+	return sourceLine{
+		text:   text,
+		number: number,
+		column: 1,
+		offset: offset,
+		pad:    len(text) - len(raw),
+	}
+}
+
+// expandIndent replaces tabs in the leading whitespace of a line with spaces.
+func expandIndent(line string) string {
+	if !strings.Contains(leadingWhitespace(line), "\t") {
+		return line
+	}
+	var b strings.Builder
+	col := 0
+	i := 0
+	for ; i < len(line); i++ {
+		switch line[i] {
+		case ' ':
+			b.WriteByte(' ')
+			col++
+			continue
+		case '\t':
+			n := tabWidth - col%tabWidth
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+			continue
+		}
+		break
+	}
+	b.WriteString(line[i:])
+	return b.String()
+}
+
+// leadingWhitespace returns the run of spaces and tabs at the start of line.
+func leadingWhitespace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// isBlank reports whether the line contains only whitespace.
+func (l sourceLine) isBlank() bool {
+	return strings.TrimSpace(l.text) == ""
+}
+
+// indent returns the number of leading spaces of the line.
+func (l sourceLine) indent() int {
+	return len(l.text) - len(strings.TrimLeft(l.text, " "))
+}
+
+// slice returns the line with the first n bytes of its text removed.
+func (l sourceLine) slice(n int) sourceLine {
+	if n > len(l.text) {
+		n = len(l.text)
+	}
+	l.column += utf8.RuneCountInString(l.text[:n])
+	// Bytes added by tab expansion have no counterpart in the source
+	padding := min(n, l.pad)
+	l.pad -= padding
+	l.offset += n - padding
+	l.text = l.text[n:]
+	return l
+}
+
+// position returns the source position of byte i of the line's text.
+func (l sourceLine) position(i int) nodes.Position {
+	if i > len(l.text) {
+		i = len(l.text)
+	}
+	padding := min(i, l.pad)
+	return nodes.Position{
+		Line:   l.number,
+		Column: l.column + utf8.RuneCountInString(l.text[:i]),
+		Offset: l.offset + i - padding,
+	}
+}
+
+// spanLines returns the source span covered by a block of lines, from the
+// first non-blank character to the end of the last non-blank line.
+func spanLines(lines []sourceLine) (nodes.Position, nodes.Position) {
+	lines = trimBlankLines(lines)
+	if len(lines) == 0 {
+		return nodes.Position{}, nodes.Position{}
+	}
+	first, last := lines[0], lines[len(lines)-1]
+	return first.position(first.indent()), last.position(len(last.text))
+}
+
+// joinLines joins the text of lines with newlines.
+func joinLines(lines []sourceLine) string {
+	texts := make([]string, len(lines))
+	for i, line := range lines {
+		texts[i] = line.text
+	}
+	return strings.Join(texts, "\n")
+}
+
+// trimBlankLines removes leading and trailing blank lines from a block.
+func trimBlankLines(lines []sourceLine) []sourceLine {
+	for len(lines) > 0 && lines[0].isBlank() {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1].isBlank() {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// lineReader walks over the lines of a single block.
+type lineReader struct {
+	lines []sourceLine
+	pos   int
+}
+
+// newLineReader creates a lineReader positioned at the first line.
+func newLineReader(lines []sourceLine) *lineReader {
+	return &lineReader{lines: lines}
+}
+
+// eof reports whether all lines have been consumed.
+func (r *lineReader) eof() bool {
+	return r.pos >= len(r.lines)
+}
+
+// peek returns the current line without consuming it.
+func (r *lineReader) peek() sourceLine {
+	return r.lines[r.pos]
+}
+
+// peekAt returns the line offset lines after the current one.
+func (r *lineReader) peekAt(offset int) (sourceLine, bool) {
+	if r.pos+offset >= len(r.lines) {
+		return sourceLine{}, false
+	}
+	return r.lines[r.pos+offset], true
+}
+
+// next consumes and returns the current line.
+func (r *lineReader) next() sourceLine {
+	line := r.lines[r.pos]
+	r.pos++
+	return line
+}
+
+// skipBlank consumes blank lines.
+func (r *lineReader) skipBlank() {
+	for !r.eof() && r.peek().isBlank() {
+		r.pos++
+	}
+}
+
+// indentedBlock consumes the indented (or blank) lines starting at the current
+// position and returns them with their common indentation removed.
+func (r *lineReader) indentedBlock() ([]sourceLine, int) {
+	return r.collectIndented(nil)
+}
+
+// firstKnownIndented consumes the lines of a block whose first line has already
+// been read and trimmed by the caller, such as a list item body following its
+// marker. The following indented lines are dedented by their common indent.
+func (r *lineReader) firstKnownIndented(first sourceLine) ([]sourceLine, int) {
+	return r.collectIndented(&first)
+}
+
+// knownIndented consumes the lines of a block whose first line has already
+// been read and trimmed by the caller, and whose indentation is known, such
+// as a list item body following its marker. The block ends at the first
+// line indented less than indent, and its lines are dedented by indent.
+func (r *lineReader) knownIndented(first sourceLine, indent int) []sourceLine {
+	start := r.pos
+	for !r.eof() && (r.peek().isBlank() || r.peek().indent() >= indent) {
+		r.pos++
+	}
+	// Leave trailing blank lines for the enclosing block.
+	for r.pos > start && r.lines[r.pos-1].isBlank() {
+		r.pos--
+	}
+
+	block := []sourceLine{first}
+	for _, line := range r.lines[start:r.pos] {
+		if line.isBlank() {
+			block = append(block, sourceLine{number: line.number, column: 1, offset: line.offset})
+			continue
+		}
+		block = append(block, line.slice(indent))
+	}
+	return block
+}
+
+func (r *lineReader) collectIndented(first *sourceLine) ([]sourceLine, int) {
+	start := r.pos
+	indent := -1
+	for !r.eof() {
+		line := r.peek()
+		if !line.isBlank() {
+			lineIndent := line.indent()
+			if lineIndent == 0 {
+				break
+			}
+			if indent < 0 || lineIndent < indent {
+				indent = lineIndent
+			}
+		}
+		r.pos++
+	}
+	// Leave trailing blank lines for the enclosing block.
+	for r.pos > start && r.lines[r.pos-1].isBlank() {
+		r.pos--
+	}
+	if indent < 0 {
+		indent = 0
+	}
+
+	block := make([]sourceLine, 0, r.pos-start+1)
+	if first != nil {
+		block = append(block, *first)
+	}
+	for _, line := range r.lines[start:r.pos] {
+		if line.isBlank() {
+			block = append(block, sourceLine{number: line.number, column: 1, offset: line.offset})
+			continue
+		}
+		block = append(block, line.slice(indent))
+	}
+	return block, indent
+}
@@ -1 +1,43 @@
 package parser
+
+import (
+	"strings"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// processReference creates a link for a phrase reference (`text`_). The
+// reference may embed its target URI, as in `text <http://example.com>`_.
+func (p *Parser) processReference(raw string, anonymous bool) *nodes.LinkNode {
+	text, target := raw, ""
+	if loc := p.patterns.embeddedURI.FindStringSubmatchIndex(raw); loc != nil {
+		text, target = raw[:loc[0]], raw[loc[2]:loc[3]]
+	}
+	text = unescape(text)
+
+	link := nodes.NewLinkNode(text, "", "")
+	link.SetAnonymous(anonymous)
+	switch {
+	case target == "":
+		link.SetRefName(normalizeName(text))
+	case strings.HasSuffix(target, "_") && !isEscaped(target, len(target)-1):
+		// An embedded alias refers to another target by name
+		link.SetRefName(normalizeName(unescape(target[:len(target)-1])))
+	default:
+		link.SetURL(strings.Join(strings.Fields(unescape(target)), ""))
+		if !anonymous {
+			// The reference also defines a target named after its text
+			link.SetRefName(normalizeName(text))
+		}
+	}
+	if text == "" {
+		link.SetContent(unescape(target))
+	}
+	return link
+}
+
+// normalizeName normalizes a reference name for comparison: case is folded
+// and runs of whitespace are collapsed to single spaces.
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
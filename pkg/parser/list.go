@@ -1,39 +1,224 @@
 package parser
 
-import "github.com/go-i2p/go-rst/pkg/nodes"
+import (
+	"strconv"
+	"strings"
 
-func (p *Parser) processListItem(content string, args []string, isOrdered bool, currentNode nodes.Node) nodes.Node {
-	indent := 0
-	if len(args) > 0 {
-		// Calculate indent from the whitespace prefix
-		indent = len(args[0])
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// enumerator is the parsed marker of an enumerated list item.
+type enumerator struct {
+	prefix   string
+	suffix   string
+	sequence nodes.EnumType
+	ordinal  int
+	auto     bool // the auto-enumerator "#"
+}
+
+// processList parses consecutive list items of the same kind into a ListNode.
+// The body of each item is parsed as a nested block, so items can hold several
+// paragraphs, sublists, code blocks and other body elements. For enumerated
+// lists it returns nil, consuming nothing, if the first line is not really a
+// list item; see isEnumeratedListItem.
+func (p *Parser) processList(r *lineReader, token Token) *nodes.ListNode {
+	ordered := token.Type == TokenEnumList
+	list := nodes.NewListNode(ordered)
+	var first enumerator
+	if ordered {
+		var ok bool
+		first, ok = p.parseEnumerator(token, "")
+		if first.auto {
+			first.ordinal = 1
+		}
+		if !ok || !p.isEnumeratedListItem(r, first) {
+			return nil
+		}
+		p.setEnumeration(list, first, r.peek())
+	} else {
+		list.SetBullet(token.Args[1])
+	}
+
+	construct := "Bullet list"
+	if ordered {
+		construct = "Enumerated list"
 	}
 
-	/*marker := ""
-	if len(args) > 1 {
-		marker = args[1]
-	}*/
+	expected := first
+	for {
+		// Items may be separated by blank lines.
+		end := r.pos
+		r.skipBlank()
+		if r.eof() {
+			break
+		}
+		line := r.peek()
+		item := p.lexer.Tokenize(line.text)
+		if !p.continuesList(r, list, item, expected) {
+			if r.pos == end {
+				p.checkBlankFinish(r, construct)
+			}
+			break
+		}
+		start := r.pos
+		r.next()
+		expected.ordinal++
+
+		// The body is indented like the text after the marker, or like its
+		// first line if it starts on the next one
+		markerWidth := len(line.text) - len(item.Content)
+		var body []sourceLine
+		indent := markerWidth
+		if item.Content != "" {
+			body = r.knownIndented(line.slice(markerWidth), markerWidth)
+		} else {
+			body, indent = r.firstKnownIndented(line.slice(markerWidth))
+		}
+		listItem := nodes.NewListItemNode("")
+		listItem.SetPosition(spanLines(r.lines[start:r.pos]))
+		for _, child := range p.parseNested(body, indent) {
+			listItem.AddChild(child)
+		}
+		list.AppendChild(listItem)
+	}
 
-	// Create a new list item node
-	listItem := nodes.NewListItemNode(content)
+	return list
+}
 
-	// If we don't have a current node or it's not a list, create a new list
-	if currentNode == nil || currentNode.Type() != nodes.NodeList {
-		listNode := nodes.NewListNode(isOrdered)
-		listNode.AppendChild(listItem)
-		return listNode
+// continuesList reports whether the current line, tokenized as item, is the
+// next item of list. Bullet items must use the same bullet; enumerated items
+// must use the same format and sequence and the expected ordinal, unless
+// they use the auto-enumerator. A list started with the auto-enumerator
+// continues only with auto-enumerated items.
+func (p *Parser) continuesList(r *lineReader, list *nodes.ListNode, item Token, expected enumerator) bool {
+	if r.peek().indent() > 0 {
+		return false
 	}
+	if !list.IsOrdered() {
+		return item.Type == TokenBulletList && item.Args[1] == list.Bullet()
+	}
+	if item.Type != TokenEnumList {
+		return false
+	}
+	next, ok := p.parseEnumerator(item, list.EnumType())
+	if !ok || next.prefix != expected.prefix || next.suffix != expected.suffix {
+		return false
+	}
+	if next.auto {
+		next.ordinal = expected.ordinal
+	} else if expected.auto || next.sequence != list.EnumType() || next.ordinal != expected.ordinal {
+		return false
+	}
+	return p.isEnumeratedListItem(r, next)
+}
 
-	// If we have a list but with different type or indent, create a new list
-	listNode := currentNode.(*nodes.ListNode)
-	if listNode.IsOrdered() != isOrdered || listNode.Indent() != indent {
-		newList := nodes.NewListNode(isOrdered)
-		newList.SetIndent(indent)
-		newList.AppendChild(listItem)
-		return newList
+// setEnumeration records the format and start value of the first enumerator
+// of list.
+func (p *Parser) setEnumeration(list *nodes.ListNode, first enumerator, line sourceLine) {
+	list.SetPrefix(first.prefix)
+	list.SetSuffix(first.suffix)
+	if first.auto {
+		return
+	}
+	list.SetEnumType(first.sequence)
+	list.SetStartValue(first.ordinal)
+	if first.ordinal != 1 {
+		p.report(LevelInfo, line.position(0), "Enumerated list start value not ordinal-1: %q (ordinal %d)",
+			nodes.FormatOrdinal(first.ordinal, first.sequence), first.ordinal)
 	}
+}
 
-	// Add item to existing list
-	listNode.AppendChild(listItem)
-	return listNode
+// isEnumeratedListItem reports whether the line starting with enum is a list
+// item rather than a paragraph that happens to start like one, as in
+// "A. Einstein was a really smart dude." The line after it must be blank,
+// indented, or the next item of the list.
+func (p *Parser) isEnumeratedListItem(r *lineReader, enum enumerator) bool {
+	next, ok := r.peekAt(1)
+	if !ok || next.isBlank() || next.indent() > 0 {
+		return true
+	}
+	token := p.lexer.Tokenize(next.text)
+	if token.Type != TokenEnumList {
+		return false
+	}
+	following, ok := p.parseEnumerator(token, enum.sequence)
+	if !ok || following.prefix != enum.prefix || following.suffix != enum.suffix {
+		return false
+	}
+	return following.auto || (following.sequence == enum.sequence && following.ordinal == enum.ordinal+1)
+}
+
+// parseEnumerator parses the enumerator of an enumerated list item token.
+// Letters that are also roman numerals are read in the preferred sequence if
+// given; otherwise "i" and "I" are roman and other single letters alphabetic.
+// It reports false if the enumerator is not valid, such as "(1." or "iiii.".
+func (p *Parser) parseEnumerator(token Token, preferred nodes.EnumType) (enumerator, bool) {
+	text, prefix, suffix := token.Args[1], token.Args[2], token.Args[3]
+	enum := enumerator{prefix: prefix, suffix: suffix}
+	if prefix == "(" && suffix != ")" {
+		return enum, false
+	}
+	if text == "#" {
+		enum.auto = true
+		enum.sequence = preferred
+		if enum.sequence == "" {
+			enum.sequence = nodes.EnumArabic
+		}
+		return enum, true
+	}
+	if ordinal, err := strconv.Atoi(text); err == nil {
+		enum.sequence, enum.ordinal = nodes.EnumArabic, ordinal
+		return enum, true
+	}
+
+	upper := text == strings.ToUpper(text)
+	alpha, roman := nodes.EnumLowerAlpha, nodes.EnumLowerRoman
+	if upper {
+		alpha, roman = nodes.EnumUpperAlpha, nodes.EnumUpperRoman
+	}
+	ordinal, isRoman := fromRoman(text)
+	isAlpha := len(text) == 1
+	if isAlpha && isRoman {
+		switch preferred {
+		case alpha:
+			isRoman = false
+		case roman:
+			isAlpha = false
+		default:
+			isAlpha = strings.ToLower(text) != "i"
+		}
+	}
+	switch {
+	case isAlpha:
+		enum.sequence, enum.ordinal = alpha, int(strings.ToLower(text)[0]-'a')+1
+	case isRoman:
+		enum.sequence, enum.ordinal = roman, ordinal
+	default:
+		return enum, false
+	}
+	return enum, true
+}
+
+// fromRoman converts roman numerals in either case to an integer. It reports
+// false if text is not a valid roman numeral.
+func fromRoman(text string) (int, bool) {
+	values := map[byte]int{'I': 1, 'V': 5, 'X': 10, 'L': 50, 'C': 100, 'D': 500, 'M': 1000}
+	upper := strings.ToUpper(text)
+	total := 0
+	for i := 0; i < len(upper); i++ {
+		value, ok := values[upper[i]]
+		if !ok {
+			return 0, false
+		}
+		if i+1 < len(upper) && values[upper[i+1]] > value {
+			total -= value
+		} else {
+			total += value
+		}
+	}
+	// Reject non-canonical forms such as "IIII" or "IC"
+	if total < 1 || nodes.FormatOrdinal(total, nodes.EnumUpperRoman) != upper {
+		return 0, false
+	}
+	return total, true
 }
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// runListTable builds a table from the content of a list-table
+// directive: a bullet list of rows, each item of which is a bullet list of
+// the cells of the row. The directive arguments form the table title. On
+// error the directive is reported and kept as a literal block.
+func runListTable(ctx *DirectiveContext) ([]nodes.Node, error) {
+	table, message := ctx.parser.buildListTable(ctx.Arguments, ctx.Options, ctx.content, ctx.indent)
+	if table == nil {
+		return nil, errors.New(message)
+	}
+	return []nodes.Node{table}, nil
+}
+
+// buildListTable does the work of runListTable. It returns nil and a
+// message if the directive is in error.
+func (p *Parser) buildListTable(args []string, options nodes.DirectiveOptions, content []sourceLine, indent int) (*nodes.TableNode, string) {
+	if len(content) == 0 {
+		return nil, `The "list-table" directive is empty; content required.`
+	}
+	body := p.parseNested(content, indent)
+	if len(body) != 1 {
+		return nil, `Error parsing content block for the "list-table" directive: exactly one bullet list expected.`
+	}
+	list, ok := body[0].(*nodes.ListNode)
+	if !ok || list.IsOrdered() {
+		return nil, `Error parsing content block for the "list-table" directive: exactly one bullet list expected.`
+	}
+
+	rows := make([]*nodes.ListNode, len(list.Children()))
+	for i, item := range list.Children() {
+		cells, ok := soleChild(item).(*nodes.ListNode)
+		if !ok || cells.IsOrdered() {
+			return nil, fmt.Sprintf(`Error parsing content block for the "list-table" directive: two-level bullet list expected, but row %d does not contain a second-level bullet list.`, i+1)
+		}
+		if i > 0 && len(cells.Children()) != len(rows[0].Children()) {
+			return nil, fmt.Sprintf(`Error parsing content block for the "list-table" directive: uniform two-level bullet list expected, but row %d does not contain the same number of items as row 1 (%d vs %d).`,
+				i+1, len(cells.Children()), len(rows[0].Children()))
+		}
+		rows[i] = cells
+	}
+	columns := len(rows[0].Children())
+
+	headerRows, message := tableCount(options, "list-table", "header-rows")
+	if message != "" {
+		return nil, message
+	}
+	stubColumns, message := tableCount(options, "list-table", "stub-columns")
+	if message != "" {
+		return nil, message
+	}
+	switch {
+	case headerRows > len(rows):
+		return nil, fmt.Sprintf(`%d header row(s) specified but only %d row(s) of data supplied ("list-table" directive).`, headerRows, len(rows))
+	case headerRows == len(rows) && headerRows > 0:
+		return nil, fmt.Sprintf(`Insufficient data supplied (%d row(s)); no data remaining for table body, required by "list-table" directive.`, len(rows))
+	case stubColumns > columns:
+		return nil, fmt.Sprintf(`%d stub column(s) specified but only %d columns(s) of data supplied ("list-table" directive).`, stubColumns, columns)
+	case stubColumns == columns && stubColumns > 0:
+		return nil, fmt.Sprintf(`Insufficient data supplied (%d columns(s)); no data remaining for table body, required by "list-table" directive.`, columns)
+	}
+
+	table := nodes.NewTableNode()
+	table.SetTitle(strings.Join(args, " "))
+	table.SetStubColumns(stubColumns)
+	if message := setTableWidths(table, "list-table", options, columns); message != "" {
+		return nil, message
+	}
+	if align, ok := options["align"]; ok {
+		if align != "left" && align != "center" && align != "right" {
+			return nil, fmt.Sprintf(`Error in "list-table" directive: invalid option value: (option: "align"; value: %q) must be "left", "center" or "right".`, align)
+		}
+		table.SetAlign(align)
+	}
+	for i, row := range rows {
+		tableRow := nodes.NewTableRowNode()
+		tableRow.SetPosition(list.Children()[i].Start(), list.Children()[i].End())
+		for _, item := range row.Children() {
+			cell := nodes.NewTableCellNode()
+			cell.SetPosition(item.Start(), item.End())
+			for _, child := range item.Children() {
+				cell.AddChild(child)
+			}
+			tableRow.AppendCell(cell)
+		}
+		if i < headerRows {
+			table.AddHeaderRow(tableRow)
+		} else {
+			table.AddBodyRow(tableRow)
+		}
+	}
+	return table, ""
+}
+
+// soleChild returns the only child of node, or nil if it has any other number
+// of children.
+func soleChild(node nodes.Node) nodes.Node {
+	if len(node.Children()) != 1 {
+		return nil
+	}
+	return node.Children()[0]
+}
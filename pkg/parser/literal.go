@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// literalQuotes lists the characters that may quote the lines of an
+// unindented literal block.
+const literalQuotes = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+// processLiteralBlock parses the literal block that follows a paragraph
+// ending in "::". It is either an indented block, whose common indentation is
+// removed, or a block of unindented lines that all start with the same
+// punctuation character, which is kept. It returns nil if neither follows.
+func (p *Parser) processLiteralBlock(r *lineReader) nodes.Node {
+	body, _ := r.indentedBlock()
+	if body = trimBlankLines(body); len(body) > 0 {
+		p.checkBlankFinish(r, "Literal block")
+		return newLiteralBlock(body)
+	}
+
+	r.skipBlank()
+	if r.eof() {
+		p.report(LevelWarning, r.lines[len(r.lines)-1].position(0), "Literal block expected; none found.")
+		return nil
+	}
+	first := r.peek()
+	if !strings.ContainsRune(literalQuotes, rune(first.text[0])) {
+		p.report(LevelWarning, first.position(0), "Literal block expected; none found.")
+		return nil
+	}
+	lines := make([]sourceLine, 0)
+	for !r.eof() && !r.peek().isBlank() {
+		line := r.peek()
+		if line.text[0] != first.text[0] {
+			p.report(LevelError, line.position(0), "Inconsistent literal block quoting.")
+			break
+		}
+		lines = append(lines, r.next())
+	}
+	return newLiteralBlock(lines)
+}
+
+// newLiteralBlock creates a code node without a language for the lines of a
+// literal block.
+func newLiteralBlock(lines []sourceLine) *nodes.CodeNode {
+	literal := nodes.NewCodeNode("", joinLines(lines), false)
+	literal.SetPosition(spanLines(lines))
+	return literal
+}
@@ -6,21 +6,36 @@ import (
 	"github.com/go-i2p/go-rst/pkg/nodes"
 )
 
-func (p *Parser) processMetaContent(line string, currentNode nodes.Node) nodes.Node {
-	if currentNode == nil || currentNode.Type() != nodes.NodeMeta {
-		return currentNode
-	}
-
-	parts := strings.SplitN(line, ":", 2)
-	if len(parts) != 2 {
-		return currentNode
-	}
+// processMeta parses the field list of a meta directive into one MetaNode per
+// field. Indented lines continue the value of the previous field.
+func (p *Parser) processMeta(r *lineReader) []nodes.Node {
+	r.next()
+	body, _ := r.indentedBlock()
+	p.checkExplicitFinish(r)
 
-	key := strings.TrimSpace(parts[0])
-	value := strings.TrimSpace(parts[1])
+	metas := make([]nodes.Node, 0)
+	var current *nodes.MetaNode
+	for _, line := range body {
+		if line.isBlank() {
+			continue
+		}
+		if current != nil && line.indent() > 0 {
+			current.SetContent(current.Content() + " " + strings.TrimSpace(line.text))
+			current.SetPosition(current.Start(), line.position(len(line.text)))
+			continue
+		}
 
-	node := nodes.NewMetaNode(key, value)
-	p.nodes = append(p.nodes, node)
-	p.context.inMeta = false
-	return nil
+		var key, value string
+		if matches := p.patterns.field.FindStringSubmatch(line.text); matches != nil {
+			key, value = matches[1], matches[2]
+		} else if parts := strings.SplitN(line.text, ":", 2); len(parts) == 2 {
+			key, value = parts[0], parts[1]
+		} else {
+			continue
+		}
+		current = nodes.NewMetaNode(strings.TrimSpace(key), strings.TrimSpace(value))
+		current.SetPosition(line.position(0), line.position(len(line.text)))
+		metas = append(metas, current)
+	}
+	return metas
 }
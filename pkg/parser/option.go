@@ -0,0 +1,61 @@
+package parser
+
+import "github.com/go-i2p/go-rst/pkg/nodes"
+
+// processOptionList parses consecutive option list items into an
+// OptionListNode. The description follows the options after at least two
+// spaces, or starts on the next, indented line, and is parsed as a nested
+// block.
+func (p *Parser) processOptionList(r *lineReader) *nodes.OptionListNode {
+	list := nodes.NewOptionListNode()
+	for {
+		// Items may be separated by blank lines.
+		end := r.pos
+		r.skipBlank()
+		if r.eof() {
+			break
+		}
+		line := r.peek()
+		token := p.lexer.Tokenize(line.text)
+		if line.indent() > 0 || token.Type != TokenOptionList {
+			if r.pos == end {
+				p.checkBlankFinish(r, "Option list")
+			}
+			break
+		}
+		start := r.pos
+		r.next()
+
+		markerWidth := len(line.text) - len(token.Content)
+		body, _ := r.firstKnownIndented(line.slice(markerWidth))
+		item := nodes.NewOptionListItemNode(p.parseOptions(token.Args[0]))
+		item.SetPosition(spanLines(r.lines[start:r.pos]))
+		for _, child := range p.parseNested(body, markerWidth) {
+			item.AddChild(child)
+		}
+		list.AppendChild(item)
+	}
+	return list
+}
+
+// parseOptions splits an option group such as "-o FILE, --output=FILE" into
+// its options.
+func (p *Parser) parseOptions(group string) []nodes.Option {
+	options := make([]nodes.Option, 0)
+	for group != "" {
+		matches := p.patterns.option.FindStringSubmatch(group)
+		if matches == nil {
+			break
+		}
+		option := nodes.Option{Name: matches[1], Delimiter: matches[2], Argument: matches[3]}
+		if option.Name == "" {
+			option = nodes.Option{Name: matches[4], Delimiter: matches[5], Argument: matches[6]}
+		}
+		options = append(options, option)
+		group = group[len(matches[0]):]
+		if len(group) >= 2 && group[:2] == ", " {
+			group = group[2:]
+		}
+	}
+	return options
+}
@@ -6,23 +6,69 @@ import (
 	"github.com/go-i2p/go-rst/pkg/nodes"
 )
 
-func (p *Parser) processParagraph(line string, currentNode nodes.Node) nodes.Node {
-	if strings.TrimSpace(line) == "" {
-		if currentNode != nil {
-			p.nodes = append(p.nodes, currentNode)
+// processParagraph collects lines up to the next blank line into a paragraph
+// and parses its inline markup into child nodes. A more indented line also
+// ends the paragraph; it starts a nested block.
+//
+// A paragraph ending in "::" introduces a literal block, which is returned
+// after it. The "::" is shown as a single colon if it directly follows text,
+// and is removed if it follows whitespace. A paragraph of only "::" is
+// removed entirely.
+func (p *Parser) processParagraph(r *lineReader) []nodes.Node {
+	lines := []sourceLine{r.next()}
+	for !r.eof() {
+		line := r.peek()
+		if line.isBlank() {
+			break
 		}
-		return nil
+		if line.indent() > 0 {
+			p.report(LevelError, line.position(line.indent()), "Unexpected indentation.")
+			break
+		}
+		lines = append(lines, r.next())
 	}
 
-	if currentNode == nil {
-		return nodes.NewParagraphNode(line)
+	text := joinLines(lines)
+	if !strings.HasSuffix(text, "::") {
+		return []nodes.Node{p.newParagraph(text, lines)}
 	}
-
-	if currentNode.Type() == nodes.NodeParagraph {
-		currentContent := currentNode.Content()
-		currentNode.SetContent(currentContent + "\n" + line)
-		return currentNode
+	result := make([]nodes.Node, 0, 2)
+	switch {
+	case text == "::":
+	case strings.ContainsAny(text[len(text)-3:len(text)-2], " \n"):
+		result = append(result, p.newParagraph(strings.TrimRight(text[:len(text)-3], " \n"), lines))
+	default:
+		result = append(result, p.newParagraph(text[:len(text)-1], lines))
 	}
+	if literal := p.processLiteralBlock(r); literal != nil {
+		result = append(result, literal)
+	}
+	return result
+}
 
-	return nodes.NewParagraphNode(line)
+// newParagraph creates a paragraph of text, the content of lines, and parses
+// its inline markup.
+func (p *Parser) newParagraph(text string, lines []sourceLine) *nodes.ParagraphNode {
+	paragraph := nodes.NewParagraphNode(text)
+	paragraph.SetPosition(spanLines(lines))
+	p.addInline(paragraph, text, lines)
+	return paragraph
+}
+
+// processTransBlock creates a paragraph from the content of a {% trans %} block,
+// translating it when a translator is available. Translated text has no exact
+// source location, so the inline nodes share the span of the block's line.
+func (p *Parser) processTransBlock(line sourceLine, content string) *nodes.ParagraphNode {
+	content = strings.TrimSpace(content)
+	// If no translator is available, keep the original content
+	if p.translator != nil {
+		content = p.translator.Translate(content)
+	}
+	paragraph := nodes.NewParagraphNode(content)
+	paragraph.SetPosition(spanLines([]sourceLine{line}))
+	p.addInline(paragraph, content, nil)
+	for _, child := range paragraph.Children() {
+		child.SetPosition(paragraph.Start(), paragraph.End())
+	}
+	return paragraph
 }
@@ -3,8 +3,9 @@
 package parser
 
 import (
-	"bufio"
-	"strings"
+	"fmt"
+	"io"
+	"path/filepath"
 
 	"github.com/go-i2p/go-rst/pkg/nodes"
 	"github.com/go-i2p/go-rst/pkg/translator"
@@ -17,130 +18,293 @@ type Parser struct {
 	context    *ParserContext
 	patterns   *Patterns
 	lexer      *Lexer
+
+	docTitle bool
+	docInfo  bool
+
+	sourcePath    string
+	fileInsertion bool
+	including     map[string]bool
+	directives    *DirectiveRegistry
+	roles         *RoleRegistry
+	localRoles    map[string]Role
+	defaultRole   string
+
+	substitutions    map[string]*substitution
+	appSubstitutions map[string]string
+	ids              map[string]nodes.Node
+
+	diagnostics []Diagnostic
+	haltLevel   Level
+	halt        *HaltError
 }
 
 // NewParser creates a new Parser instance.
 func NewParser(trans translator.Translator) *Parser {
 	return &Parser{
-		nodes:      make([]nodes.Node, 0),
-		translator: trans,
-		context:    NewParserContext(),
-		patterns:   NewPatterns(),
-		lexer:      NewLexer(),
+		nodes:         make([]nodes.Node, 0),
+		translator:    trans,
+		context:       NewParserContext(),
+		patterns:      NewPatterns(),
+		lexer:         NewLexer(),
+		docTitle:      true,
+		docInfo:       true,
+		haltLevel:     LevelNone,
+		fileInsertion: true,
+		directives:    NewDirectiveRegistry(),
+		roles:         NewRoleRegistry(),
+		localRoles:    make(map[string]Role),
+		defaultRole:   defaultRole,
+
+		substitutions:    make(map[string]*substitution),
+		appSubstitutions: make(map[string]string),
+		ids:              make(map[string]nodes.Node),
 	}
 }
 
-// Parse takes a string of reStructuredText content and returns a slice of Node instances.
+// SetHaltLevel sets the diagnostic level at which parsing stops. The default,
+// LevelNone, never stops; LevelError makes any error fatal.
+func (p *Parser) SetHaltLevel(level Level) {
+	p.haltLevel = level
+}
+
+// SetSourcePath sets the path of the document being parsed. Files named by
+// directives, such as the "file" option of csv-table, are relative to its
+// directory; without a source path they are relative to the working
+// directory.
+func (p *Parser) SetSourcePath(path string) {
+	p.sourcePath = path
+}
+
+// SetFileInsertion sets whether directives may read files. It is enabled by
+// default; disable it when parsing untrusted input.
+func (p *Parser) SetFileInsertion(enabled bool) {
+	p.fileInsertion = enabled
+}
+
+// Directives returns the directives the parser understands. Register a
+// directive with it to extend the parser:
+//
+//	p.Directives().Register("router", parser.Directive{
+//		RequiredArguments: 1,
+//		HasContent:        true,
+//		Options:           parser.OptionSpec{"version": parser.UnchangedRequired},
+//		Run: func(ctx *parser.DirectiveContext) ([]nodes.Node, error) {
+//			return ctx.ParseContent(), nil
+//		},
+//	})
+func (p *Parser) Directives() *DirectiveRegistry {
+	return p.directives
+}
+
+// Roles returns the interpreted text roles the parser understands. Register
+// a role with it to extend the parser. Roles defined by a document with the
+// role directive apply to that document only.
+func (p *Parser) Roles() *RoleRegistry {
+	return p.roles
+}
+
+// SetSubstitution defines the substitution |name| for every document the
+// parser reads, such as a release version kept by the application. The text
+// is parsed as inline reStructuredText. A document's own definition of the
+// same name takes precedence.
+func (p *Parser) SetSubstitution(name, text string) {
+	p.appSubstitutions[normalizeName(name)] = text
+}
+
+// IDs returns the elements of the most recent parse that links may point to,
+// by ID: sections, the document title, hyperlink targets, footnotes and
+// footnote references.
+func (p *Parser) IDs() map[string]nodes.Node {
+	return p.ids
+}
+
+// Diagnostics returns the diagnostics reported by the most recent parse.
+func (p *Parser) Diagnostics() []Diagnostic {
+	return p.diagnostics
+}
+
+// Parse takes a string of reStructuredText content and returns the top-level
+// nodes of the document. Nested constructs such as list item bodies, block
+// quotes and directive content are attached as children of their parent node.
+// Every node records the span of source text it was parsed from.
+// Problems found in the input are recorded and available from Diagnostics.
 func (p *Parser) Parse(content string) []nodes.Node {
-	scanner := bufio.NewScanner(strings.NewReader(content))
-	var currentNode nodes.Node
-	var prevToken Token
-	p.nodes = make([]nodes.Node, 0) // Clear existing nodes
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		token := p.lexer.Tokenize(line)
-
-		if newNode := p.processToken(token, prevToken, currentNode, line); newNode != nil {
-			// Only append if we actually have a new node
-			if currentNode != nil && currentNode != newNode {
-				p.nodes = append(p.nodes, currentNode)
-			}
-			currentNode = newNode
-		}
-		prevToken = token
+	return p.parseDocument(splitLines(content))
+}
+
+// ParseReader reads reStructuredText from r and parses it like Parse. Lines
+// may be of any length, and a leading byte order mark and CRLF line endings
+// are accepted. The error is either the first read error, in which case no
+// nodes are returned, or a *HaltError as returned by ParseWithDiagnostics.
+func (p *Parser) ParseReader(r io.Reader) ([]nodes.Node, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		p.diagnostics, p.halt = nil, nil
+		return nil, fmt.Errorf("reading input: %w", err)
+	}
+	result := p.parseDocument(lines)
+	if p.halt != nil {
+		return result, p.halt
 	}
+	return result, nil
+}
 
-	// Add final node if exists and not already added
-	if currentNode != nil && (len(p.nodes) == 0 || p.nodes[len(p.nodes)-1] != currentNode) {
-		p.nodes = append(p.nodes, currentNode)
+// ParseWithDiagnostics parses content like Parse and also returns the
+// diagnostics reported for it. The error is a *HaltError if a diagnostic
+// reached the halt level, in which case the nodes only cover the input parsed
+// up to that point.
+func (p *Parser) ParseWithDiagnostics(content string) ([]nodes.Node, []Diagnostic, error) {
+	result := p.Parse(content)
+	if p.halt != nil {
+		return result, p.diagnostics, p.halt
 	}
+	return result, p.diagnostics, nil
+}
 
+// parseDocument parses the lines of a whole document, resetting the state
+// left by any earlier parse.
+func (p *Parser) parseDocument(lines []sourceLine) []nodes.Node {
+	p.context.Reset()
+	p.diagnostics = nil
+	p.halt = nil
+	p.localRoles = make(map[string]Role)
+	p.defaultRole = defaultRole
+	p.substitutions = make(map[string]*substitution)
+	p.ids = make(map[string]nodes.Node)
+	// The document itself may not be included
+	p.including = make(map[string]bool)
+	if p.sourcePath != "" {
+		p.including[filepath.Clean(p.sourcePath)] = true
+	}
+	p.nodes = nestSections(p.parseBlocks(lines))
+	p.checkTransitions(p.nodes, true)
+	if p.docTitle {
+		var title int
+		if p.nodes, title = p.promoteTitle(p.nodes); title >= 0 {
+			p.nodes = p.promoteSubtitle(p.nodes, title)
+		}
+	}
+	if p.docInfo {
+		p.promoteDocInfo(p.nodes)
+	}
+	p.resolveSubstitutions(p.nodes, make(map[*substitution]bool))
+	p.identify(p.nodes)
+	p.resolveFootnotes(p.nodes)
+	p.resolveReferences(p.nodes)
 	return p.nodes
 }
 
-func (p *Parser) processToken(token, prevToken Token, currentNode nodes.Node, originalLine string) nodes.Node {
-	// translatedContent := p.translator.Translate(token.Content)
-	// token.Content = translatedContent
-	switch token.Type {
-	case TokenBulletList:
-		return p.processListItem(token.Content, token.Args, false, currentNode)
-	case TokenEnumList:
-		return p.processListItem(token.Content, token.Args, true, currentNode)
-	case TokenBlockQuote:
-		return p.processBlockQuote(token.Content, token.Args, currentNode)
-	case TokenComment:
-		return nodes.NewCommentNode(token.Content)
-	case TokenTransBlock:
-		// Always create a new node for translation blocks
-		content := strings.TrimSpace(token.Content)
-		if p.translator != nil {
-			translatedContent := p.translator.Translate(content)
-			return nodes.NewParagraphNode(translatedContent)
-		}
-		// If no translator is available, return the original content
-		return nodes.NewParagraphNode(content)
-	case TokenHeadingUnderline:
-		if prevToken.Type == TokenText {
-			return p.processHeading(prevToken.Content, token.Content)
-		}
+// sourceRelative resolves a path named in the document relative to the
+// document's directory.
+func (p *Parser) sourceRelative(path string) string {
+	if filepath.IsAbs(path) || p.sourcePath == "" {
+		return path
+	}
+	return filepath.Join(filepath.Dir(p.sourcePath), path)
+}
 
-	case TokenMeta:
-		p.context.inMeta = true
-		return nodes.NewMetaNode("", "")
-
-	case TokenCodeBlock:
-		p.context.inCodeBlock = true
-		p.context.codeBlockIndent = 4
-		language := ""
-		if len(token.Args) > 0 {
-			language = token.Args[0]
+// parseBlocks parses a block of lines into body elements.
+func (p *Parser) parseBlocks(lines []sourceLine) []nodes.Node {
+	result := make([]nodes.Node, 0)
+	r := newLineReader(lines)
+	for p.halt == nil {
+		r.skipBlank()
+		if r.eof() {
+			break
 		}
-		return nodes.NewCodeNode(language, "", false)
-
-	case TokenDirective:
-		p.context.inDirective = true
-		p.context.currentDirective = token.Content
-		return nodes.NewDirectiveNode(token.Content, token.Args)
+		start := r.pos
+		blockNodes := p.parseBlock(r)
+		for _, node := range blockNodes {
+			if !node.Start().IsValid() {
+				node.SetPosition(spanLines(r.lines[start:r.pos]))
+			}
+		}
+		result = append(result, blockNodes...)
+	}
+	return result
+}
 
-	case TokenEmphasis:
-		// Process the emphasized text
-		return p.processEmphasis(token.Content)
+// parseNested parses the body of an enclosing construct whose lines were
+// indented by indent columns. The indentation is tracked on the context so
+// that constructs only valid at the top level, such as sections, can tell
+// where they are.
+func (p *Parser) parseNested(lines []sourceLine, indent int) []nodes.Node {
+	p.context.pushIndent(indent)
+	defer p.context.popIndent()
+	return p.parseBlocks(lines)
+}
 
-	case TokenStrong:
-		// Process the strong (bold) text
-		return p.processStrong(token.Content)
+// parseBlock parses the body element starting at the current line and
+// consumes its lines.
+func (p *Parser) parseBlock(r *lineReader) []nodes.Node {
+	line := r.peek()
+	if line.indent() > 0 {
+		return []nodes.Node{p.processBlockQuote(r)}
+	}
 
-	case TokenLineBlock:
-		// Check if we're already in a line block node
-		if lineBlock, ok := currentNode.(*nodes.LineBlockNode); ok {
-			// Add this line to the existing line block
-			lines := lineBlock.Lines()
-			lines = append(lines, token.Content)
-			newLineBlock := nodes.NewLineBlockNode(lines)
-			return newLineBlock
+	token := p.lexer.Tokenize(line.text)
+	if token.Type == TokenSimpleTable && isAdornment(r) {
+		token.Type = TokenTransition
+	}
+	switch token.Type {
+	case TokenHeadingUnderline, TokenTransition:
+		if transition := p.processTransition(r); transition != nil {
+			return []nodes.Node{transition}
 		}
-		// Create a new line block node
-		return nodes.NewLineBlockNode([]string{token.Content})
-
+		fallthrough
 	case TokenText:
-		if p.context.inCodeBlock {
-			return p.processCodeBlock(originalLine, currentNode) // Use original line to preserve indentation
-		}
-		if p.context.inMeta {
-			return p.processMetaContent(token.Content, currentNode)
+		if heading := p.processHeading(r); heading != nil {
+			return []nodes.Node{heading}
 		}
-		if p.context.inDirective {
-			return p.processDirectiveContent(token.Content, currentNode)
-		}
-		return p.processParagraph(token.Content, currentNode)
-	case TokenTransition:
-		// For transitions, we create a new transition node with the character used
-		if len(token.Content) > 0 {
-			return p.processTransition(token.Content)
+	}
+
+	switch token.Type {
+	case TokenTransBlock:
+		return []nodes.Node{p.processTransBlock(r.next(), token.Content)}
+	case TokenMeta:
+		return p.processMeta(r)
+	case TokenCodeBlock, TokenDirective:
+		return p.processDirective(r)
+	case TokenFootnote:
+		return []nodes.Node{p.processFootnote(r)}
+	case TokenTarget, TokenAnonymousTarget:
+		return []nodes.Node{p.processTarget(r)}
+	case TokenSubstitutionDef:
+		return p.processSubstitutionDef(r)
+	case TokenComment:
+		return []nodes.Node{p.processComment(r, token)}
+	case TokenBulletList:
+		return []nodes.Node{p.processList(r, token)}
+	case TokenEnumList:
+		if list := p.processList(r, token); list != nil {
+			return []nodes.Node{list}
 		}
-		return nodes.NewTransitionNode('-') // Default to hyphen if empty
+	case TokenField:
+		return []nodes.Node{p.processFieldList(r)}
+	case TokenGridTable:
+		return []nodes.Node{p.processGridTable(r)}
+	case TokenSimpleTable:
+		return []nodes.Node{p.processSimpleTable(r)}
+	case TokenOptionList:
+		return []nodes.Node{p.processOptionList(r)}
+	case TokenDoctest:
+		return []nodes.Node{p.processDoctestBlock(r)}
+	case TokenLineBlock:
+		return []nodes.Node{p.processLineBlock(r)}
 	}
 
-	return currentNode
+	switch token.Type {
+	case TokenText, TokenHeadingUnderline, TokenTransition:
+	default:
+		// A marker that does not start its construct, like an enumerator
+		// followed by an unindented line, may still begin a section title
+		if heading := p.processHeading(r); heading != nil {
+			return []nodes.Node{heading}
+		}
+	}
+	if p.isDefinitionListItem(r) {
+		return []nodes.Node{p.processDefinitionList(r)}
+	}
+	return p.processParagraph(r)
 }
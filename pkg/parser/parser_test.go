@@ -4,6 +4,9 @@ package parser
 // Use example restructuredText files embedded in the test functions
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -68,23 +71,75 @@ func TestParseStrongText(t *testing.T) {
 	content := "This is **bold text** in a sentence."
 
 	doc := parser.Parse(content)
-	if len(doc) == 0 {
-		t.Errorf("Expected parsed nodes, got empty document")
+	if len(doc) != 1 || doc[0].Type() != nodes.NodeParagraph {
+		t.Fatalf("Expected a single paragraph, got %d nodes", len(doc))
 	}
 
-	// Check that we have a strong node
-	foundStrong := false
-	for _, node := range doc {
-		if node.Type() == nodes.NodeStrong {
-			foundStrong = true
-			if node.Content() != "bold text" {
-				t.Errorf("Expected strong content to be 'bold text', got '%s'", node.Content())
-			}
+	// The strong text is an inline child of the paragraph and the
+	// surrounding text is kept
+	children := doc[0].Children()
+	if len(children) != 3 {
+		t.Fatalf("Expected 3 inline nodes, got %d", len(children))
+	}
+	if children[0].Type() != nodes.NodeText || children[0].Content() != "This is " {
+		t.Errorf("Expected leading text 'This is ', got '%s'", children[0].Content())
+	}
+	if children[1].Type() != nodes.NodeStrong || children[1].Content() != "bold text" {
+		t.Errorf("Expected strong content to be 'bold text', got '%s'", children[1].Content())
+	}
+	if children[2].Type() != nodes.NodeText || children[2].Content() != " in a sentence." {
+		t.Errorf("Expected trailing text ' in a sentence.', got '%s'", children[2].Content())
+	}
+}
+
+func TestParseInlineMarkup(t *testing.T) {
+	parser := NewParser(nil)
+	content := "*em* ``x*y`` :code:`go` `Go <https://go.dev>`_ see_ |name| \\*not em*"
+
+	doc := parser.Parse(content)
+	if len(doc) != 1 {
+		t.Fatalf("Expected a single paragraph, got %d nodes", len(doc))
+	}
+
+	var types []nodes.NodeType
+	for _, child := range doc[0].Children() {
+		if child.Type() != nodes.NodeText {
+			types = append(types, child.Type())
+		}
+	}
+	expected := []nodes.NodeType{
+		nodes.NodeEmphasis,
+		nodes.NodeLiteral,
+		nodes.NodeInline,
+		nodes.NodeLink,
+		nodes.NodeLink,
+		nodes.NodeSubstitutionReference,
+	}
+	if len(types) != len(expected) {
+		t.Fatalf("Expected %d inline markup nodes, got %d", len(expected), len(types))
+	}
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Errorf("Inline node %d: expected type %d, got %d", i, expected[i], types[i])
 		}
 	}
 
-	if !foundStrong {
-		t.Errorf("Expected to find a strong node in parsed document")
+	children := doc[0].Children()
+	if link, ok := children[6].(*nodes.LinkNode); !ok || link.URL() != "https://go.dev" || link.Content() != "Go" {
+		t.Errorf("Expected embedded link to https://go.dev, got %v", children[6])
+	}
+	last := children[len(children)-1]
+	if last.Content() != " *not em*" {
+		t.Errorf("Expected escaped text ' *not em*', got '%s'", last.Content())
+	}
+
+	// Escaped whitespace may end interpreted text, but not emphasis
+	doc, diagnostics, _ := parser.ParseWithDiagnostics(":literal:`\\  : \\ ` and *x\\ *")
+	if literal := doc[0].Children()[0]; literal.Type() != nodes.NodeLiteral || literal.Content() != " : " {
+		t.Errorf("Expected the literal ' : ', got %v", literal)
+	}
+	if len(diagnostics) != 1 || !strings.Contains(diagnostics[0].Message, "Inline emphasis start-string without end-string") {
+		t.Errorf("Expected only the emphasis to be unterminated, got %v", diagnostics)
 	}
 }
 
@@ -126,3 +181,1290 @@ End of test.`
 		t.Errorf("Expected to find a code node in parsed document")
 	}
 }
+
+func TestParseNestedList(t *testing.T) {
+	parser := NewParser(nil)
+	content := `- first item
+
+  second paragraph of the first item
+
+  - nested item
+  - another nested item
+
+- second item`
+
+	doc := parser.Parse(content)
+	if len(doc) != 1 || doc[0].Type() != nodes.NodeList {
+		t.Fatalf("Expected a single list, got %d nodes", len(doc))
+	}
+	items := doc[0].Children()
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 list items, got %d", len(items))
+	}
+
+	first := items[0].Children()
+	if len(first) != 3 {
+		t.Fatalf("Expected 3 children in the first item, got %d", len(first))
+	}
+	if first[0].Type() != nodes.NodeParagraph || first[0].Content() != "first item" {
+		t.Errorf("Expected first paragraph 'first item', got '%s'", first[0].Content())
+	}
+	if first[1].Content() != "second paragraph of the first item" {
+		t.Errorf("Expected second paragraph, got '%s'", first[1].Content())
+	}
+	if first[2].Type() != nodes.NodeList || len(first[2].Children()) != 2 {
+		t.Errorf("Expected a nested list with 2 items")
+	}
+}
+
+func TestParseListItemBlocks(t *testing.T) {
+	parser := NewParser(nil)
+	content := `- Example::
+
+      code
+
+- Quote:
+
+      quoted text
+
+-
+  Body on the next line.`
+
+	doc, diagnostics, _ := parser.ParseWithDiagnostics(content)
+	if len(doc) != 1 || len(doc[0].Children()) != 3 {
+		t.Fatalf("Expected a list of 3 items, got %v", doc)
+	}
+	items := doc[0].Children()
+	if body := items[0].Children(); len(body) != 2 || body[1].Type() != nodes.NodeCode || body[1].Content() != "code" {
+		t.Errorf("Expected a paragraph and a literal block, got %v", body)
+	}
+	if body := items[1].Children(); len(body) != 2 || body[1].Type() != nodes.NodeBlockQuote {
+		t.Errorf("Expected a paragraph and a block quote, got %v", body)
+	}
+	if body := items[2].Children(); len(body) != 1 || body[0].Content() != "Body on the next line." {
+		t.Errorf("Expected the body of a bare bullet on the next line, got %v", body)
+	}
+	if len(diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics, got %v", diagnostics)
+	}
+}
+
+func TestParseBlockQuote(t *testing.T) {
+	parser := NewParser(nil)
+	content := `Introduction.
+
+    Quoted paragraph.
+
+    * quoted list item
+
+    -- Attribution`
+
+	doc := parser.Parse(content)
+	if len(doc) != 2 {
+		t.Fatalf("Expected 2 nodes, got %d", len(doc))
+	}
+	quote, ok := doc[1].(*nodes.BlockQuoteNode)
+	if !ok {
+		t.Fatalf("Expected a block quote, got %T", doc[1])
+	}
+	if quote.Attribution() != "Attribution" {
+		t.Errorf("Expected attribution 'Attribution', got '%s'", quote.Attribution())
+	}
+	children := quote.Children()
+	if len(children) != 2 || children[0].Type() != nodes.NodeParagraph || children[1].Type() != nodes.NodeList {
+		t.Errorf("Expected a paragraph and a list inside the block quote")
+	}
+}
+
+func TestParseDirectiveBody(t *testing.T) {
+	parser := NewParser(nil)
+	content := `.. note::
+
+   First paragraph.
+
+   .. code-block:: go
+
+      fmt.Println("nested")
+
+After the note.`
+
+	doc := parser.Parse(content)
+	if len(doc) != 2 {
+		t.Fatalf("Expected 2 nodes, got %d", len(doc))
+	}
+	note, ok := doc[0].(*nodes.AdmonitionNode)
+	if !ok {
+		t.Fatalf("Expected an admonition, got %T", doc[0])
+	}
+	if note.Kind() != "note" || note.Title() != "Note" {
+		t.Errorf("Expected a note titled 'Note', got %q %q", note.Kind(), note.Title())
+	}
+	children := note.Children()
+	if len(children) != 2 {
+		t.Fatalf("Expected 2 children in the note, got %d", len(children))
+	}
+	code, ok := children[1].(*nodes.CodeNode)
+	if !ok {
+		t.Fatalf("Expected a nested code block, got %T", children[1])
+	}
+	if code.Content() != `fmt.Println("nested")` {
+		t.Errorf("Unexpected code content '%s'", code.Content())
+	}
+	if doc[1].Content() != "After the note." {
+		t.Errorf("Expected trailing paragraph, got '%s'", doc[1].Content())
+	}
+}
+
+func TestParsePositions(t *testing.T) {
+	parser := NewParser(nil)
+	content := "Title\n=====\n\n- item with **bold**\n  and more\n"
+
+	doc := parser.Parse(content)
+	if len(doc) != 2 {
+		t.Fatalf("Expected 2 nodes, got %d", len(doc))
+	}
+
+	heading := doc[0]
+	if heading.Start() != (nodes.Position{Line: 1, Column: 1, Offset: 0}) {
+		t.Errorf("Unexpected heading start %v", heading.Start())
+	}
+	if heading.End() != (nodes.Position{Line: 2, Column: 6, Offset: 11}) {
+		t.Errorf("Unexpected heading end %v", heading.End())
+	}
+
+	paragraph := doc[1].Children()[0].Children()[0]
+	if paragraph.Start() != (nodes.Position{Line: 4, Column: 3, Offset: 15}) {
+		t.Errorf("Unexpected paragraph start %v", paragraph.Start())
+	}
+	if paragraph.End() != (nodes.Position{Line: 5, Column: 11, Offset: 44}) {
+		t.Errorf("Unexpected paragraph end %v", paragraph.End())
+	}
+
+	strong := paragraph.Children()[1]
+	if strong.Start() != (nodes.Position{Line: 4, Column: 13, Offset: 25}) {
+		t.Errorf("Unexpected strong start %v", strong.Start())
+	}
+	if got := content[strong.Start().Offset:strong.End().Offset]; got != "**bold**" {
+		t.Errorf("Expected strong span to cover '**bold**', got '%s'", got)
+	}
+}
+
+func TestParseWithDiagnostics(t *testing.T) {
+	parser := NewParser(nil)
+	content := `Paragraph with *unclosed emphasis.
+
+.. frobnicate::
+
+.. code-block:: go
+
+------
+Orphan underline
+`
+
+	doc, diagnostics, err := parser.ParseWithDiagnostics(content)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(doc) == 0 {
+		t.Fatal("Expected nodes despite the diagnostics")
+	}
+	expected := []struct {
+		level Level
+		line  int
+	}{
+		{LevelWarning, 1},
+		{LevelError, 3},
+		{LevelError, 5},
+		{LevelSevere, 7},
+	}
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("Expected %d diagnostics, got %v", len(expected), diagnostics)
+	}
+	for i, want := range expected {
+		got := diagnostics[i]
+		if got.Level != want.level || got.Position.Line != want.line {
+			t.Errorf("Diagnostic %d: expected %s on line %d, got %s", i, want.level, want.line, got)
+		}
+	}
+
+	parser.SetHaltLevel(LevelError)
+	doc, _, err = parser.ParseWithDiagnostics(content)
+	var halt *HaltError
+	if !errors.As(err, &halt) {
+		t.Fatalf("Expected a HaltError, got %v", err)
+	}
+	if halt.Diagnostic.Position.Line != 3 {
+		t.Errorf("Expected to halt on line 3, got %s", halt.Diagnostic)
+	}
+	if len(doc) != 2 {
+		t.Errorf("Expected parsing to stop after the unknown directive, got %d nodes", len(doc))
+	}
+}
+
+// failingReader returns some data and then an error.
+type failingReader struct {
+	data string
+	err  error
+}
+
+func (r *failingReader) Read(b []byte) (int, error) {
+	if r.data == "" {
+		return 0, r.err
+	}
+	n := copy(b, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestParseReader(t *testing.T) {
+	parser := NewParser(nil)
+	long := strings.Repeat("word ", 20000)
+	content := "\uFEFFTitle\r\n=====\r\n\r\n" + long + "\r\n\r\nLast paragraph.\r\n"
+
+	doc, err := parser.ParseReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(doc) != 3 {
+		t.Fatalf("Expected 3 nodes, got %d", len(doc))
+	}
+	if doc[0].Type() != nodes.NodeTitle || doc[0].Content() != "Title" {
+		t.Errorf("Expected title 'Title', got %v '%s'", doc[0].Type(), doc[0].Content())
+	}
+	if doc[1].Content() != strings.TrimSpace(long) {
+		t.Errorf("Long line was truncated to %d bytes", len(doc[1].Content()))
+	}
+	if doc[2].Content() != "Last paragraph." {
+		t.Errorf("Expected last paragraph, got '%s'", doc[2].Content())
+	}
+	if offset := doc[0].Start().Offset; offset != len("\uFEFF") {
+		t.Errorf("Expected the title to start after the byte order mark, got offset %d", offset)
+	}
+
+	readErr := errors.New("disk on fire")
+	_, err = parser.ParseReader(&failingReader{data: "Partial\n", err: readErr})
+	if !errors.Is(err, readErr) {
+		t.Errorf("Expected the read error, got %v", err)
+	}
+}
+
+func TestParseSectionLevels(t *testing.T) {
+	parser := NewParser(nil)
+	parser.SetDocTitle(false)
+	content := `##########
+ Document
+##########
+
+Chapter
+*******
+
+Section
+^^^^^^^
+
+Another chapter
+***************
+
+Skipped level
+=============
+`
+
+	doc, diagnostics, err := parser.ParseWithDiagnostics(content)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []struct {
+		title string
+		level int
+		depth int
+	}{
+		{"Document", 1, 0},
+		{"Chapter", 2, 1},
+		{"Section", 3, 2},
+		{"Another chapter", 2, 1},
+		{"Skipped level", 4, 2},
+	}
+	type nested struct {
+		section *nodes.SectionNode
+		depth   int
+	}
+	var sections []nested
+	var walk func(body []nodes.Node, depth int)
+	walk = func(body []nodes.Node, depth int) {
+		for _, node := range body {
+			if section, ok := node.(*nodes.SectionNode); ok {
+				sections = append(sections, nested{section, depth})
+				walk(section.Body(), depth+1)
+			}
+		}
+	}
+	walk(doc, 0)
+	if len(doc) != 1 || len(sections) != len(expected) {
+		t.Fatalf("Expected %d sections in one top-level section, got %d in %d nodes",
+			len(expected), len(sections), len(doc))
+	}
+	for i, want := range expected {
+		heading := sections[i].section.Heading()
+		if heading.Content() != want.title || heading.Level() != want.level || sections[i].depth != want.depth {
+			t.Errorf("Expected '%s' at level %d and depth %d, got '%s' at level %d and depth %d",
+				want.title, want.level, want.depth, heading.Content(), heading.Level(), sections[i].depth)
+		}
+	}
+	heading := sections[0].section.Heading()
+	if heading.Start().Line != 1 || heading.End().Line != 3 {
+		t.Errorf("Expected the overlined title to span lines 1-3, got %s-%s", heading.Start(), heading.End())
+	}
+	if end := doc[0].End().Line; end != 15 {
+		t.Errorf("Expected the document section to end on line 15, got %d", end)
+	}
+	if len(diagnostics) != 1 || diagnostics[0].Level != LevelSevere || diagnostics[0].Position.Line != 14 {
+		t.Errorf("Expected an inconsistent level on line 14, got %v", diagnostics)
+	}
+}
+
+func TestParseDocTitle(t *testing.T) {
+	content := `.. A comment may precede the title
+
+=========
+ Project
+=========
+
+A *useful* tool
+---------------
+
+Introduction
+~~~~~~~~~~~~
+
+Text.`
+
+	parser := NewParser(nil)
+	doc := parser.Parse(content)
+	if len(doc) != 4 {
+		t.Fatalf("Expected 4 nodes, got %d", len(doc))
+	}
+	title, ok := doc[1].(*nodes.TitleNode)
+	if !ok || title.Content() != "Project" {
+		t.Fatalf("Expected title 'Project', got %v", doc[1])
+	}
+	subtitle, ok := doc[2].(*nodes.SubtitleNode)
+	if !ok {
+		t.Fatalf("Expected a subtitle, got %T", doc[2])
+	}
+	if len(subtitle.Children()) != 3 || subtitle.Children()[1].Type() != nodes.NodeEmphasis {
+		t.Errorf("Expected the subtitle to keep its inline markup, got %v", subtitle.Children())
+	}
+	section, ok := doc[3].(*nodes.SectionNode)
+	if !ok || section.Level() != 3 || len(section.Body()) != 1 {
+		t.Errorf("Expected a level 3 section holding one paragraph, got %v", doc[3])
+	}
+
+	parser.SetDocTitle(false)
+	doc = parser.Parse(content)
+	if len(doc) != 2 || doc[1].Type() != nodes.NodeSection {
+		t.Errorf("Expected a comment and a section with the transform disabled, got %v", doc)
+	}
+}
+
+func TestParseEnumeratedLists(t *testing.T) {
+	parser := NewParser(nil)
+	content := `3. Three
+4. Four
+
+   (a) Sub a
+   (b) Sub b
+
+ii) Two
+iii) Three
+
+#. Auto one
+#. Auto two
+
+A. Einstein was a really
+smart dude.
+
+1. Section
+==========`
+
+	doc := parser.Parse(content)
+	if len(doc) != 5 {
+		t.Fatalf("Expected 5 nodes, got %d", len(doc))
+	}
+	outer, ok := doc[0].(*nodes.ListNode)
+	if !ok || len(outer.Children()) != 2 {
+		t.Fatalf("Expected a list of 2 items, got %v", doc[0])
+	}
+	if outer.StartValue() != 3 || outer.EnumType() != nodes.EnumArabic || outer.Marker(1) != "4." {
+		t.Errorf("Expected arabic numbering from 3, got %s from %d", outer.EnumType(), outer.StartValue())
+	}
+	sub, ok := outer.Children()[1].Children()[1].(*nodes.ListNode)
+	if !ok {
+		t.Fatalf("Expected a sublist in the second item, got %v", outer.Children()[1].Children())
+	}
+	if sub.EnumType() != nodes.EnumLowerAlpha || sub.Marker(1) != "(b)" {
+		t.Errorf("Expected a parenthesized alphabetic sublist, got %s with marker %s", sub.EnumType(), sub.Marker(1))
+	}
+
+	auto := doc[2].(*nodes.ListNode)
+	if len(auto.Children()) != 2 || auto.StartValue() != 1 {
+		t.Errorf("Expected an auto-numbered list of 2 items, got %d items from %d", len(auto.Children()), auto.StartValue())
+	}
+	roman := doc[1].(*nodes.ListNode)
+	if roman.EnumType() != nodes.EnumLowerRoman || roman.StartValue() != 2 || roman.Marker(1) != "iii)" {
+		t.Errorf("Expected roman numbering from 2, got %s from %d", roman.EnumType(), roman.StartValue())
+	}
+	if doc[3].Type() != nodes.NodeParagraph {
+		t.Errorf("Expected a paragraph, got %v", doc[3])
+	}
+	if section, ok := doc[4].(*nodes.SectionNode); !ok || nodes.TextContent(section.Heading()) != "1. Section" {
+		t.Errorf("Expected a section titled with an enumerator, got %v", doc[4])
+	}
+}
+
+func TestParseDefinitionList(t *testing.T) {
+	parser := NewParser(nil)
+	content := `term 1
+    Definition 1.
+
+*term 2* : classifier one : two
+    Definition 2, paragraph 1.
+
+    Definition 2, paragraph 2.
+
+Paragraph after the list.`
+
+	doc := parser.Parse(content)
+	if len(doc) != 2 {
+		t.Fatalf("Expected 2 nodes, got %d", len(doc))
+	}
+	list, ok := doc[0].(*nodes.DefinitionListNode)
+	if !ok || len(list.Children()) != 2 {
+		t.Fatalf("Expected a definition list with 2 items, got %v", doc[0])
+	}
+	item := list.Children()[1].(*nodes.DefinitionListItemNode)
+	if item.Term().Content() != "term 2" || item.Term().Children()[0].Type() != nodes.NodeEmphasis {
+		t.Errorf("Expected an emphasized term 'term 2', got %v", item.Term().Children())
+	}
+	classifiers := item.Classifiers()
+	if len(classifiers) != 2 || classifiers[0] != "classifier one" || classifiers[1] != "two" {
+		t.Errorf("Expected classifiers [classifier one two], got %v", classifiers)
+	}
+	if len(item.Definition()) != 2 {
+		t.Errorf("Expected a definition of 2 paragraphs, got %d elements", len(item.Definition()))
+	}
+	if doc[1].Type() != nodes.NodeParagraph {
+		t.Errorf("Expected a trailing paragraph, got %v", doc[1])
+	}
+}
+
+func TestParseDocInfo(t *testing.T) {
+	content := `==========
+ My Post
+==========
+
+:Authors: Jane Doe; John Roe
+:Date: 2024-01-01
+:Version: 1.2
+:Tags: i2p, privacy
+:Abstract:
+    A short summary.
+
+    In two paragraphs.
+
+Body.
+
+:Not: docinfo`
+
+	parser := NewParser(nil)
+	doc := parser.Parse(content)
+	if len(doc) != 4 {
+		t.Fatalf("Expected 4 nodes, got %d", len(doc))
+	}
+	docInfo, ok := doc[1].(*nodes.DocInfoNode)
+	if !ok {
+		t.Fatalf("Expected docinfo after the title, got %T", doc[1])
+	}
+	if docInfo.Author() != "Jane Doe, John Roe" || len(docInfo.Authors()) != 2 {
+		t.Errorf("Expected two authors, got %v", docInfo.Authors())
+	}
+	if docInfo.Date() != "2024-01-01" || docInfo.Version() != "1.2" {
+		t.Errorf("Expected date and version, got '%s' and '%s'", docInfo.Date(), docInfo.Version())
+	}
+	if len(docInfo.Abstract()) != 2 {
+		t.Errorf("Expected an abstract of 2 paragraphs, got %d elements", len(docInfo.Abstract()))
+	}
+	if len(docInfo.Children()) != 5 {
+		t.Errorf("Expected docinfo to keep all 5 fields, got %d", len(docInfo.Children()))
+	}
+	if list, ok := doc[3].(*nodes.FieldListNode); !ok || list.Children()[0].(*nodes.FieldNode).Name() != "Not" {
+		t.Errorf("Expected a plain field list after the body, got %v", doc[3])
+	}
+
+	parser.SetDocInfo(false)
+	doc = parser.Parse(content)
+	if doc[1].Type() != nodes.NodeFieldList {
+		t.Errorf("Expected a field list with the transform disabled, got %T", doc[1])
+	}
+}
+
+func TestParseOptionList(t *testing.T) {
+	parser := NewParser(nil)
+	content := `-a            Output all.
+-f FILE, --file=FILE  Read from FILE.
+--very-long-option
+              Description on the next line.
+/V            DOS-style option.
+
+-1 is not an option.`
+
+	doc := parser.Parse(content)
+	if len(doc) != 2 {
+		t.Fatalf("Expected 2 nodes, got %d", len(doc))
+	}
+	list, ok := doc[0].(*nodes.OptionListNode)
+	if !ok || len(list.Children()) != 4 {
+		t.Fatalf("Expected an option list with 4 items, got %v", doc[0])
+	}
+	item := list.Children()[1].(*nodes.OptionListItemNode)
+	options := item.Options()
+	if len(options) != 2 {
+		t.Fatalf("Expected 2 options, got %v", options)
+	}
+	if options[0] != (nodes.Option{Name: "-f", Delimiter: " ", Argument: "FILE"}) {
+		t.Errorf("Expected option -f FILE, got %+v", options[0])
+	}
+	if options[1] != (nodes.Option{Name: "--file", Delimiter: "=", Argument: "FILE"}) {
+		t.Errorf("Expected option --file=FILE, got %+v", options[1])
+	}
+	if len(item.Description()) != 1 || item.Description()[0].Content() != "Read from FILE." {
+		t.Errorf("Expected description 'Read from FILE.', got %v", item.Description())
+	}
+	long := list.Children()[2].(*nodes.OptionListItemNode)
+	if long.OptionGroup() != "--very-long-option" || len(long.Description()) != 1 {
+		t.Errorf("Expected --very-long-option with a description, got %v", long)
+	}
+	if doc[1].Type() != nodes.NodeParagraph {
+		t.Errorf("Expected a trailing paragraph, got %v", doc[1])
+	}
+}
+
+func TestParseGridTable(t *testing.T) {
+	parser := NewParser(nil)
+	content := `+----------+----------+-----------+
+| Header 1 | Header 2 | Header 3  |
++==========+==========+===========+
+| spans two columns   | spans     |
++----------+----------+ two rows  |
+| - item   | cell     |           |
+| - item   |          |           |
++----------+----------+-----------+`
+
+	doc, diagnostics, _ := parser.ParseWithDiagnostics(content)
+	if len(diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics, got %v", diagnostics)
+	}
+	if len(doc) != 1 {
+		t.Fatalf("Expected 1 node, got %d", len(doc))
+	}
+	table, ok := doc[0].(*nodes.TableNode)
+	if !ok {
+		t.Fatalf("Expected a table, got %v", doc[0])
+	}
+	if table.Columns() != 3 || len(table.HeaderRows()) != 1 || len(table.BodyRows()) != 2 {
+		t.Fatalf("Expected 3 columns, 1 header row and 2 body rows, got %v", table)
+	}
+	if headers := table.Headers(); len(headers) != 3 || headers[2] != "Header 3" {
+		t.Errorf("Expected headers [Header 1 Header 2 Header 3], got %v", headers)
+	}
+	first := table.BodyRows()[0].Cells()
+	if len(first) != 2 || first[0].ColSpan() != 2 || first[1].RowSpan() != 2 {
+		t.Errorf("Expected a cell spanning 2 columns and one spanning 2 rows, got %v", first)
+	}
+	second := table.BodyRows()[1].Cells()
+	if len(second) != 2 || second[0].Children()[0].Type() != nodes.NodeList {
+		t.Errorf("Expected the first cell of the last row to hold a list, got %v", second)
+	}
+}
+
+func TestParseMalformedGridTable(t *testing.T) {
+	parser := NewParser(nil)
+	content := `+-----+-----+
+| a   | b
++-----+-----+`
+
+	doc, diagnostics, _ := parser.ParseWithDiagnostics(content)
+	if len(doc) != 1 || doc[0].Type() != nodes.NodeCode {
+		t.Fatalf("Expected the table to be kept as a literal block, got %v", doc)
+	}
+	if len(diagnostics) != 1 || diagnostics[0].Level != LevelError {
+		t.Errorf("Expected a malformed table error, got %v", diagnostics)
+	}
+}
+
+func TestParseSimpleTable(t *testing.T) {
+	parser := NewParser(nil)
+	content := `=====  =====  ======
+   Inputs     Output
+------------  ------
+  A      B    A or B
+=====  =====  ======
+False  False  False
+True   False  True,
+              if B is
+              false
+=====  =====  ======`
+
+	doc, diagnostics, _ := parser.ParseWithDiagnostics(content)
+	if len(diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics, got %v", diagnostics)
+	}
+	if len(doc) != 1 {
+		t.Fatalf("Expected 1 node, got %d", len(doc))
+	}
+	table, ok := doc[0].(*nodes.TableNode)
+	if !ok {
+		t.Fatalf("Expected a table, got %v", doc[0])
+	}
+	if table.Columns() != 3 || len(table.HeaderRows()) != 2 || len(table.BodyRows()) != 2 {
+		t.Fatalf("Expected 3 columns, 2 header rows and 2 body rows, got %v", table)
+	}
+	if inputs := table.HeaderRows()[0].Cells(); len(inputs) != 2 || inputs[0].ColSpan() != 2 {
+		t.Errorf("Expected the Inputs header to span 2 columns, got %v", inputs)
+	}
+	rows := table.Rows()
+	if rows[1][2] != "True,\nif B is\nfalse" {
+		t.Errorf("Expected a continued last row, got %q", rows[1][2])
+	}
+
+	// A one-column table, unlike a title overline, has no matching underline
+	doc, diagnostics, _ = parser.ParseWithDiagnostics("=====\nOne\nTwo\n=====")
+	if table, ok := doc[0].(*nodes.TableNode); !ok || table.Columns() != 1 || len(table.BodyRows()) != 2 || len(diagnostics) != 0 {
+		t.Errorf("Expected a one-column table with 2 rows, got %v and %v", doc, diagnostics)
+	}
+}
+
+func TestParseCSVTable(t *testing.T) {
+	content := `.. csv-table:: Prices
+   :header: "Treat", "Quantity"
+   :widths: 3, 1
+
+   "Crunchy Frog", 1.49
+   "Gannet Ripple, large", 1.99
+   Spam`
+
+	parser := NewParser(nil)
+	doc, diagnostics, _ := parser.ParseWithDiagnostics(content)
+	if len(diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics, got %v", diagnostics)
+	}
+	table, ok := doc[0].(*nodes.TableNode)
+	if !ok {
+		t.Fatalf("Expected a table, got %v", doc[0])
+	}
+	if table.Title() != "Prices" || len(table.ColumnWidths()) != 2 {
+		t.Errorf("Expected title 'Prices' and 2 column widths, got %q %v", table.Title(), table.ColumnWidths())
+	}
+	if headers := table.Headers(); len(headers) != 2 || headers[0] != "Treat" {
+		t.Errorf("Expected headers [Treat Quantity], got %v", headers)
+	}
+	rows := table.Rows()
+	if len(rows) != 3 || rows[1][0] != "Gannet Ripple, large" || len(rows[2]) != 2 || rows[2][1] != "" {
+		t.Errorf("Expected 3 rows with the short row padded, got %q", rows)
+	}
+}
+
+func TestParseCSVTableFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "matrix.csv"), []byte("Router;Speed\ni2pd;fast\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	content := `.. csv-table::
+   :file: matrix.csv
+   :delim: ;
+   :header-rows: 1`
+
+	parser := NewParser(nil)
+	parser.SetSourcePath(filepath.Join(dir, "doc.rst"))
+	doc, diagnostics, _ := parser.ParseWithDiagnostics(content)
+	if len(diagnostics) != 0 {
+		t.Fatalf("Expected no diagnostics, got %v", diagnostics)
+	}
+	table := doc[0].(*nodes.TableNode)
+	if len(table.HeaderRows()) != 1 || len(table.Rows()) != 1 || table.Rows()[0][0] != "i2pd" {
+		t.Errorf("Expected a header row and a row for i2pd, got %v %q", table.Headers(), table.Rows())
+	}
+
+	parser.SetFileInsertion(false)
+	doc, diagnostics, _ = parser.ParseWithDiagnostics(content)
+	if len(diagnostics) != 1 || doc[0].Type() != nodes.NodeCode {
+		t.Errorf("Expected file access to be refused, got %v", diagnostics)
+	}
+}
+
+func TestParseInclude(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"part.rst":  "Part\n----\n\nIncluded *text*.\n\n.. include:: doc.rst\n",
+		"notes.txt": "skipped\nSTART\nkept\nEND\n",
+	}
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	content := `.. include:: part.rst
+
+.. include:: notes.txt
+   :literal:
+   :start-after: START
+   :end-before: END
+
+.. raw:: html
+
+   <hr>
+
+.. contents::`
+
+	parser := NewParser(nil)
+	parser.SetSourcePath(filepath.Join(dir, "doc.rst"))
+	doc, diagnostics, _ := parser.ParseWithDiagnostics(content)
+	if len(doc) != 5 || doc[0].Type() != nodes.NodeTitle {
+		t.Fatalf("Expected the included section to become the title, got %v", doc)
+	}
+	body := doc[1:]
+	if body[0].Type() != nodes.NodeParagraph {
+		t.Fatalf("Expected the included paragraph, a literal block and raw HTML, got %v", body)
+	}
+	if body[2].Type() != nodes.NodeCode || body[2].Content() != "kept" {
+		t.Errorf("Expected the selected lines as a literal block, got %v", body[2])
+	}
+	if raw, ok := body[3].(*nodes.RawNode); !ok || !raw.HasFormat("html") || raw.Content() != "<hr>" {
+		t.Errorf("Expected raw HTML, got %v", body[3])
+	}
+	if len(diagnostics) != 2 ||
+		!strings.Contains(diagnostics[0].Message, "Circular inclusion") ||
+		!strings.Contains(diagnostics[1].Message, `The "contents" directive is not supported.`) {
+		t.Errorf("Expected circular inclusion and unsupported directive diagnostics, got %v", diagnostics)
+	}
+}
+
+func TestParseListTable(t *testing.T) {
+	content := `.. list-table:: Versions
+   :header-rows: 1
+   :stub-columns: 1
+   :widths: 2 1
+   :align: center
+
+   * - Router
+     - Version
+   * - i2pd
+     - 2.50
+
+       Second paragraph.
+   * - Java I2P
+     - 2.4.0
+
+.. list-table::
+
+   * - a
+     - b
+   * - c`
+
+	parser := NewParser(nil)
+	doc, diagnostics, _ := parser.ParseWithDiagnostics(content)
+	if len(doc) != 2 {
+		t.Fatalf("Expected 2 nodes, got %d", len(doc))
+	}
+	table, ok := doc[0].(*nodes.TableNode)
+	if !ok {
+		t.Fatalf("Expected a table, got %v", doc[0])
+	}
+	if table.Title() != "Versions" || table.StubColumns() != 1 || table.Align() != "center" {
+		t.Errorf("Expected title, stub column and alignment, got %q %d %q", table.Title(), table.StubColumns(), table.Align())
+	}
+	if len(table.HeaderRows()) != 1 || len(table.BodyRows()) != 2 || len(table.ColumnWidths()) != 2 {
+		t.Errorf("Expected 1 header row, 2 body rows and 2 column widths, got %v", table)
+	}
+	if cell := table.BodyRows()[0].Cells()[1]; len(cell.Children()) != 2 {
+		t.Errorf("Expected a cell of 2 paragraphs, got %d children", len(cell.Children()))
+	}
+	if doc[1].Type() != nodes.NodeCode || len(diagnostics) != 1 || diagnostics[0].Level != LevelError {
+		t.Errorf("Expected a non-uniform list table to be an error, got %v %v", doc[1], diagnostics)
+	}
+
+	// Content that parses to no elements is an error, not a crash
+	doc, diagnostics, _ = parser.ParseWithDiagnostics(".. list-table::\n\n   .. |x| replace:: y")
+	if len(doc) != 1 || doc[0].Type() != nodes.NodeCode || len(diagnostics) != 1 ||
+		!strings.Contains(diagnostics[0].Message, "exactly one bullet list expected") {
+		t.Errorf("Expected an empty list table to be an error, got %v %v", doc, diagnostics)
+	}
+}
+
+func TestParseLiteralBlocks(t *testing.T) {
+	content := `Partially minimized::
+
+    for i in range(3):
+        print(i)
+
+Fully minimized ::
+
+    code
+
+::
+
+    expanded
+
+Quoted::
+
+> quoted
+> lines`
+
+	parser := NewParser(nil)
+	doc, diagnostics, _ := parser.ParseWithDiagnostics(content)
+	if len(diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics, got %v", diagnostics)
+	}
+	expected := []struct {
+		nodeType nodes.NodeType
+		content  string
+	}{
+		{nodes.NodeParagraph, "Partially minimized:"},
+		{nodes.NodeCode, "for i in range(3):\n    print(i)"},
+		{nodes.NodeParagraph, "Fully minimized"},
+		{nodes.NodeCode, "code"},
+		{nodes.NodeCode, "expanded"},
+		{nodes.NodeParagraph, "Quoted:"},
+		{nodes.NodeCode, "> quoted\n> lines"},
+	}
+	if len(doc) != len(expected) {
+		t.Fatalf("Expected %d nodes, got %d", len(expected), len(doc))
+	}
+	for i, want := range expected {
+		if doc[i].Type() != want.nodeType || doc[i].Content() != want.content {
+			t.Errorf("Node %d: expected %v %q, got %v %q", i, want.nodeType, want.content, doc[i].Type(), doc[i].Content())
+		}
+	}
+	if code := doc[1].(*nodes.CodeNode); code.Language() != "" {
+		t.Errorf("Expected a literal block without a language, got %q", code.Language())
+	}
+}
+
+func TestParseDoctestBlock(t *testing.T) {
+	content := `>>> def double(x):
+...     return x * 2
+>>> double(2)
+4
+>>> print("a\nb")
+a
+b
+
+After the block.`
+
+	parser := NewParser(nil)
+	doc := parser.Parse(content)
+	if len(doc) != 2 {
+		t.Fatalf("Expected 2 nodes, got %d", len(doc))
+	}
+	doctest, ok := doc[0].(*nodes.DoctestNode)
+	if !ok {
+		t.Fatalf("Expected DoctestNode, got %T", doc[0])
+	}
+	examples := doctest.Examples()
+	if len(examples) != 3 {
+		t.Fatalf("Expected 3 examples, got %d", len(examples))
+	}
+	if examples[0].Command != "def double(x):" || len(examples[0].Continuation) != 1 ||
+		examples[0].Continuation[0] != "    return x * 2" || examples[0].Expected != "" {
+		t.Errorf("Unexpected first example: %+v", examples[0])
+	}
+	if examples[1].Command != "double(2)" || examples[1].Expected != "4" {
+		t.Errorf("Unexpected second example: %+v", examples[1])
+	}
+	if examples[2].Expected != "a\nb" {
+		t.Errorf("Expected multi-line output, got %q", examples[2].Expected)
+	}
+	if doctest.Command() != "def double(x):\n    return x * 2" {
+		t.Errorf("Unexpected command: %q", doctest.Command())
+	}
+	if doc[1].Type() != nodes.NodeParagraph {
+		t.Errorf("Expected the block to end at the blank line, got %v", doc[1].Type())
+	}
+}
+
+func TestParseDirectiveParts(t *testing.T) {
+	content := `.. table:: Router
+   configuration
+   :class: Tip
+   :width: 50%
+
+   First paragraph.
+
+   Second paragraph.
+
+.. code:: go extra`
+
+	parser := NewParser(nil)
+	doc, diagnostics, _ := parser.ParseWithDiagnostics(content)
+	if len(doc) != 2 {
+		t.Fatalf("Expected 2 nodes, got %d", len(doc))
+	}
+	directive, ok := doc[0].(*nodes.DirectiveNode)
+	if !ok {
+		t.Fatalf("Expected a directive, got %T", doc[0])
+	}
+	if args := directive.Arguments(); len(args) != 1 || args[0] != "Router\nconfiguration" {
+		t.Errorf("Expected the title as a single argument, got %q", args)
+	}
+	options := directive.Options()
+	if options.Value("class") != "tip" {
+		t.Errorf("Expected class option 'tip', got %q", options.Value("class"))
+	}
+	if width, ok, err := options.Length("width"); !ok || err != nil || width != (nodes.Length{Value: 50, Unit: "%"}) {
+		t.Errorf("Expected a width of 50%%, got %v %v", width, err)
+	}
+	if len(directive.Children()) != 2 {
+		t.Errorf("Expected 2 paragraphs of content, got %d", len(directive.Children()))
+	}
+	if doc[1].Type() != nodes.NodeCode || len(diagnostics) != 1 || diagnostics[0].Level != LevelError {
+		t.Errorf("Expected too many arguments to be an error, got %v %v", doc[1], diagnostics)
+	}
+}
+
+func TestDirectiveRegistry(t *testing.T) {
+	content := `.. router:: i2pd
+   :since: 2.50
+
+   Supported.
+
+.. router::
+
+.. image:: logo.png
+   :width: 50%
+   :colour: red`
+
+	parser := NewParser(nil)
+	parser.Directives().Register("Router", Directive{
+		RequiredArguments: 1,
+		HasContent:        true,
+		Options:           OptionSpec{"since": UnchangedRequired},
+		Run: func(ctx *DirectiveContext) ([]nodes.Node, error) {
+			if err := ctx.RequireContent(); err != nil {
+				return nil, err
+			}
+			note := nodes.NewAdmonitionNode("note", ctx.Arguments[0]+" "+ctx.Options.Value("since"))
+			for _, child := range ctx.ParseContent() {
+				note.AddChild(child)
+			}
+			return []nodes.Node{note}, nil
+		},
+	})
+	doc, diagnostics, _ := parser.ParseWithDiagnostics(content)
+	if len(doc) != 3 {
+		t.Fatalf("Expected 3 nodes, got %d", len(doc))
+	}
+	note, ok := doc[0].(*nodes.AdmonitionNode)
+	if !ok || note.Title() != "i2pd 2.50" || len(note.Children()) != 1 {
+		t.Errorf("Expected the registered directive to build a note, got %v", doc[0])
+	}
+	if len(diagnostics) != 2 {
+		t.Fatalf("Expected 2 diagnostics, got %v", diagnostics)
+	}
+	if !strings.Contains(diagnostics[0].Message, "1 argument(s) required") {
+		t.Errorf("Expected a missing argument error, got %q", diagnostics[0].Message)
+	}
+	if !strings.Contains(diagnostics[1].Message, `unknown option: "colour"`) {
+		t.Errorf("Expected an unknown option error, got %q", diagnostics[1].Message)
+	}
+	if doc[1].Type() != nodes.NodeCode || doc[2].Type() != nodes.NodeCode {
+		t.Errorf("Expected directives in error to be kept as literal blocks")
+	}
+}
+
+func TestParseRoles(t *testing.T) {
+	content := `.. role:: python(code)
+   :language: python
+
+.. role:: highlight
+
+.. default-role:: strong
+
+:sub:` + "`2`" + ` :python:` + "`len(x)`" + ` :highlight:` + "`note`" + ` ` + "`bold`" + ` :rfc:` + "`1149`" + ` :unknown:` + "`x`"
+
+	parser := NewParser(nil)
+	parser.Roles().Register("i2p", Role{Run: func(ctx *RoleContext) ([]nodes.Node, error) {
+		return []nodes.Node{nodes.NewLinkNode(ctx.Text, "https://geti2p.net/"+ctx.Text, "")}, nil
+	}})
+	doc, diagnostics, _ := parser.ParseWithDiagnostics(content + " :i2p:`docs`")
+	if len(doc) != 1 {
+		t.Fatalf("Expected a single paragraph, got %d nodes", len(doc))
+	}
+	var markup []nodes.Node
+	for _, child := range doc[0].Children() {
+		if child.Type() != nodes.NodeText {
+			markup = append(markup, child)
+		}
+	}
+	if len(markup) != 6 {
+		t.Fatalf("Expected 6 inline markup nodes, got %d", len(markup))
+	}
+	if sub, ok := markup[0].(*nodes.InlineNode); !ok || sub.Kind() != nodes.InlineSubscript {
+		t.Errorf("Expected a subscript, got %v", markup[0])
+	}
+	if code, ok := markup[1].(*nodes.InlineNode); !ok || code.Kind() != nodes.InlineCode || code.Language() != "python" {
+		t.Errorf("Expected python code, got %v", markup[1])
+	}
+	if span, ok := markup[2].(*nodes.InlineNode); !ok || span.Kind() != nodes.InlineSpan || len(span.Classes()) != 1 || span.Classes()[0] != "highlight" {
+		t.Errorf("Expected a span classed 'highlight', got %v", markup[2])
+	}
+	if markup[3].Type() != nodes.NodeStrong {
+		t.Errorf("Expected the default role to be strong, got %v", markup[3])
+	}
+	if link, ok := markup[4].(*nodes.LinkNode); !ok || link.URL() != "https://tools.ietf.org/html/rfc1149" {
+		t.Errorf("Expected a link to RFC 1149, got %v", markup[4])
+	}
+	if link, ok := markup[5].(*nodes.LinkNode); !ok || link.URL() != "https://geti2p.net/docs" {
+		t.Errorf("Expected the registered role to build a link, got %v", markup[5])
+	}
+	if len(diagnostics) != 1 || !strings.Contains(diagnostics[0].Message, `Unknown interpreted text role "unknown"`) {
+		t.Errorf("Expected an unknown role error, got %v", diagnostics)
+	}
+
+	// Roles and the default role do not carry over to the next document
+	doc = parser.Parse("`title` :highlight:`x`")
+	if doc[0].Children()[0].Type() != nodes.NodeInterpretedText || len(parser.Diagnostics()) != 1 {
+		t.Errorf("Expected the document roles to be reset, got %v", parser.Diagnostics())
+	}
+
+	// A role derived from a derived role overrides its options
+	doc = parser.Parse(`.. role:: python(code)
+   :language: python
+
+.. role:: py3(python)
+   :language: python3
+
+.. role:: py(python)
+
+:py3:` + "`print()`" + ` :py:` + "`print`")
+	for i, want := range map[int][2]string{0: {"python3", "py3"}, 2: {"python", "py"}} {
+		code, ok := doc[0].Children()[i].(*nodes.InlineNode)
+		if !ok || code.Language() != want[0] || strings.Join(code.Classes(), " ") != want[1] {
+			t.Errorf("Expected %s code classed %q, got %v", want[0], want[1], doc[0].Children()[i])
+		}
+	}
+}
+
+func TestParseSubstitutions(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "0")
+	content := `Release |version| of |project| on |today|, |copy| |logo| |missing|.
+
+.. |version| replace:: *1.2*
+.. |copy| unicode:: U+00A9 .. copyright sign
+.. |today| date:: %d %B %Y
+.. |logo| image:: logo.png
+.. |loop| replace:: |loop|
+
+|loop|`
+
+	parser := NewParser(nil)
+	parser.SetSubstitution("project", "go-rst")
+	parser.SetSubstitution("version", "0.0")
+	doc, diagnostics, _ := parser.ParseWithDiagnostics(content)
+	if len(doc) != 2 {
+		t.Fatalf("Expected the definitions to add no nodes, got %d nodes", len(doc))
+	}
+	var refs []*nodes.SubstitutionReferenceNode
+	for _, child := range doc[0].Children() {
+		if ref, ok := child.(*nodes.SubstitutionReferenceNode); ok {
+			refs = append(refs, ref)
+		}
+	}
+	if len(refs) != 6 {
+		t.Fatalf("Expected 6 substitution references, got %d", len(refs))
+	}
+	if children := refs[0].Children(); len(children) != 1 || children[0].Type() != nodes.NodeEmphasis {
+		t.Errorf("Expected the document definition of |version| to be used, got %v", children)
+	}
+	for i, want := range map[int]string{1: "go-rst", 2: "01 January 1970", 3: "©"} {
+		if got := nodes.TextContent(refs[i]); got != want {
+			t.Errorf("Expected |%s| to be %q, got %q", refs[i].Name(), want, got)
+		}
+	}
+	if children := refs[4].Children(); len(children) != 1 || children[0].Type() != nodes.NodeImage {
+		t.Errorf("Expected |logo| to be an image, got %v", children)
+	}
+	if len(refs[5].Children()) != 0 {
+		t.Errorf("Expected |missing| to stay unresolved, got %v", refs[5].Children())
+	}
+	if len(diagnostics) != 2 ||
+		!strings.Contains(diagnostics[0].Message, `Undefined substitution referenced: "missing"`) ||
+		!strings.Contains(diagnostics[1].Message, `Circular substitution definition referenced: "loop"`) {
+		t.Errorf("Expected undefined and circular substitution errors, got %v", diagnostics)
+	}
+
+	// Each reference has its own copy of the definition
+	doc = parser.Parse("|a| and |a|\n\n.. |a| replace:: *x*")
+	first, second := doc[0].Children()[0], doc[0].Children()[2]
+	if len(first.Children()) != 1 || len(second.Children()) != 1 || first.Children()[0] == second.Children()[0] {
+		t.Errorf("Expected each reference to have its own nodes, got %v and %v", first.Children(), second.Children())
+	}
+
+	// Changing a copy leaves the original unchanged
+	original := nodes.NewInlineNode(nodes.InlineSpan, "")
+	original.SetClasses([]string{"a"})
+	original.AddChild(nodes.NewTextNode("text"))
+	copied := nodes.CopyInline(original).(*nodes.InlineNode)
+	copied.Classes()[0] = "b"
+	copied.Children()[0].SetContent("changed")
+	copied.AddChild(nodes.NewTextNode("more"))
+	if original.Classes()[0] != "a" || len(original.Children()) != 1 || original.Children()[0].Content() != "text" {
+		t.Errorf("Expected the original to be unchanged, got %v", original)
+	}
+}
+
+func TestParseFootnotes(t *testing.T) {
+	content := `Text [#]_ [#note]_ [1]_ [*]_ [CIT2002]_ [#note]_ [9]_.
+
+.. [1] Manual.
+.. [#] First auto-numbered.
+.. [#note] Named, auto-numbered.
+.. [*] Symbol.
+.. [CIT2002] Citation.`
+
+	parser := NewParser(nil)
+	doc, diagnostics, _ := parser.ParseWithDiagnostics(content)
+	if len(doc) != 6 {
+		t.Fatalf("Expected a paragraph and 5 footnotes, got %d nodes", len(doc))
+	}
+	var refs []*nodes.FootnoteReferenceNode
+	for _, child := range doc[0].Children() {
+		if ref, ok := child.(*nodes.FootnoteReferenceNode); ok {
+			refs = append(refs, ref)
+		}
+	}
+	if len(refs) != 7 {
+		t.Fatalf("Expected 7 footnote references, got %d", len(refs))
+	}
+	for i, want := range []string{"2", "3", "1", "*", "CIT2002", "3"} {
+		if refs[i].Label() != want {
+			t.Errorf("Reference %d: expected label %q, got %q", i, want, refs[i].Label())
+		}
+	}
+	named := doc[3].(*nodes.FootnoteNode)
+	if named.Label() != "3" || refs[1].RefID() != named.ID() || len(named.Backrefs()) != 2 || named.Backrefs()[1] != refs[5].ID() {
+		t.Errorf("Expected [#note] to link both ways with its two references, got %v %v", named, named.Backrefs())
+	}
+	if citation := doc[5].(*nodes.FootnoteNode); !citation.IsCitation() || refs[4].RefID() != citation.ID() {
+		t.Errorf("Expected the citation reference to link to the citation, got %v", citation)
+	}
+	if refs[6].RefID() != "" {
+		t.Errorf("Expected [9]_ to stay unresolved, got %q", refs[6].RefID())
+	}
+	if len(diagnostics) != 1 || !strings.Contains(diagnostics[0].Message, `Unknown target name: "9"`) {
+		t.Errorf("Expected an unknown target error, got %v", diagnostics)
+	}
+
+	// Substitutions may contain footnote references, each copy its own
+	doc, diagnostics, _ = parser.ParseWithDiagnostics("|x| and |x|.\n\n.. |x| replace:: see [#]_\n.. [#] One.\n.. [#] Two.")
+	var labels []string
+	for _, child := range doc[0].Children() {
+		if child.Type() != nodes.NodeSubstitutionReference {
+			continue
+		}
+		for _, node := range child.Children() {
+			if ref, ok := node.(*nodes.FootnoteReferenceNode); ok {
+				labels = append(labels, ref.Label())
+			}
+		}
+	}
+	if strings.Join(labels, " ") != "1 2" || len(diagnostics) != 0 {
+		t.Errorf("Expected references to footnotes 1 and 2, got %v %v", labels, diagnostics)
+	}
+}
+
+func TestParseHyperlinkTargets(t *testing.T) {
+	content := "See Python_, `docs`_, `anon`__, `Go <https://go.dev>`_ and Go_, `alias`_, `loop`_,\n" +
+		"missing_, https://geti2p.net/. or mail@example.org.\n\n" +
+		`.. _Python: https://www.python.org/
+.. _docs: https://docs.example.com/
+   long/path
+__ https://anon.example.com/
+.. _alias: Python_
+.. _loop: loop_`
+
+	parser := NewParser(nil)
+	doc, diagnostics, _ := parser.ParseWithDiagnostics(content)
+	var links []*nodes.LinkNode
+	for _, child := range doc[0].Children() {
+		if link, ok := child.(*nodes.LinkNode); ok {
+			links = append(links, link)
+		}
+	}
+	expected := []string{
+		"https://www.python.org/",
+		"https://docs.example.com/long/path",
+		"https://anon.example.com/",
+		"https://go.dev",
+		"https://go.dev",
+		"https://www.python.org/",
+		"",
+		"",
+		"https://geti2p.net/",
+		"mailto:mail@example.org",
+	}
+	if len(links) != len(expected) {
+		t.Fatalf("Expected %d links, got %d", len(expected), len(links))
+	}
+	for i, want := range expected {
+		if links[i].URL() != want {
+			t.Errorf("Link %d (%s): expected URL %q, got %q", i, links[i].Content(), want, links[i].URL())
+		}
+	}
+	if target, ok := doc[len(doc)-2].(*nodes.TargetNode); !ok || target.RefName() != "python" {
+		t.Errorf("Expected an indirect target to Python, got %v", doc[len(doc)-2])
+	}
+	if len(diagnostics) != 2 ||
+		!strings.Contains(diagnostics[0].Message, "which is a circular reference") ||
+		!strings.Contains(diagnostics[1].Message, `Unknown target name: "missing"`) {
+		t.Errorf("Expected circular and unknown target errors, got %v", diagnostics)
+	}
+}
+
+func TestSectionIDs(t *testing.T) {
+	content := `Intro
+=====
+
+See ` + "`Café Crème`_" + ` and label_.
+
+.. _label:
+
+Café Crème
+==========
+
+Intro
+=====`
+
+	parser := NewParser(nil)
+	doc, diagnostics, _ := parser.ParseWithDiagnostics(content)
+	var ids []string
+	for _, node := range doc {
+		ids = append(ids, node.ID())
+	}
+	if strings.Join(ids, " ") != "intro cafe-creme intro-1" {
+		t.Errorf("Expected IDs intro, cafe-creme and intro-1, got %q", ids)
+	}
+	if parser.IDs()["cafe-creme"] != doc[1] || parser.IDs()["label"] == nil {
+		t.Errorf("Expected the ID map to hold the section, got %v", parser.IDs()["cafe-creme"])
+	}
+
+	var links []*nodes.LinkNode
+	for _, child := range doc[0].Children()[1].Children() {
+		if link, ok := child.(*nodes.LinkNode); ok {
+			links = append(links, link)
+		}
+	}
+	if len(links) != 2 || links[0].RefID() != "cafe-creme" || links[1].RefID() != "label" {
+		t.Errorf("Expected links to cafe-creme and label, got %v", links)
+	}
+	if len(diagnostics) != 1 || diagnostics[0].Level != LevelInfo ||
+		!strings.Contains(diagnostics[0].Message, `Duplicate implicit target name: "intro"`) {
+		t.Errorf("Expected a duplicate implicit target note, got %v", diagnostics)
+	}
+}
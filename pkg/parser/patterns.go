@@ -4,50 +4,124 @@ package parser
 
 import (
 	"regexp"
+	"strings"
 )
 
+// simpleName matches reference names and role names: alphanumerics joined by
+// isolated hyphens, underscores, periods, colons and plus signs.
+const simpleName = `[\pL\pN]+(?:[-_.:+][\pL\pN]+)*`
+
+// adornmentChars are the punctuation characters that may make up section
+// title adornments and transitions.
+const adornmentChars = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+// repeatedChar returns a pattern matching a line made of one character from
+// chars, repeated.
+func repeatedChar(chars string) string {
+	alternatives := make([]string, 0, len(chars))
+	for _, c := range chars {
+		alternatives = append(alternatives, regexp.QuoteMeta(string(c))+"+")
+	}
+	return "^(?:" + strings.Join(alternatives, "|") + ")$"
+}
+
+// footnoteLabel matches the label of a footnote or citation: a number, "#"
+// with an optional name, "*", or a citation name.
+const footnoteLabel = `[0-9]+|#(?:` + simpleName + `)?|\*|` + simpleName
+
+// optionArgument matches the argument placeholder of a command-line option.
+const optionArgument = `[a-zA-Z][a-zA-Z0-9_-]*|<[^<>]+>`
+
+// option matches a short ("-a", "+a") or long ("--all", "/A") command-line
+// option with an optional argument. The groups hold the name, delimiter and
+// argument of short options, then those of long options.
+const option = `([-+][a-zA-Z0-9])(?:( ?)(` + optionArgument + `))?|` +
+	`((?:--|/)[a-zA-Z0-9][a-zA-Z0-9_-]*)(?:([ =])(` + optionArgument + `))?`
+
 // Patterns holds compiled regular expressions for parsing Markdown syntax.
 type Patterns struct {
-	headingUnderline *regexp.Regexp
-	transBlock       *regexp.Regexp
-	meta             *regexp.Regexp
-	directive        *regexp.Regexp
-	codeBlock        *regexp.Regexp
-	blockQuote       *regexp.Regexp
-	doctest          *regexp.Regexp
-	doctestContinue  *regexp.Regexp
-	doctestOutput    *regexp.Regexp
-	emphasis         *regexp.Regexp
-	strong           *regexp.Regexp
-	lineBlock        *regexp.Regexp
-	comment          *regexp.Regexp
-	title            *regexp.Regexp
-	subtitle         *regexp.Regexp
-	transition       *regexp.Regexp
-	bulletList       *regexp.Regexp
-	enumList         *regexp.Regexp
+	adornment          *regexp.Regexp
+	transBlock         *regexp.Regexp
+	meta               *regexp.Regexp
+	directive          *regexp.Regexp
+	codeBlock          *regexp.Regexp
+	blockQuote         *regexp.Regexp
+	doctest            *regexp.Regexp
+	doctestContinue    *regexp.Regexp
+	lineBlock          *regexp.Regexp
+	comment            *regexp.Regexp
+	footnote           *regexp.Regexp
+	footnoteReference  *regexp.Regexp
+	target             *regexp.Regexp
+	anonymousTarget    *regexp.Regexp
+	indirectTarget     *regexp.Regexp
+	standaloneURI      *regexp.Regexp
+	email              *regexp.Regexp
+	substitutionDef    *regexp.Regexp
+	embeddedDirective  *regexp.Regexp
+	unicodeCode        *regexp.Regexp
+	unicodeComment     *regexp.Regexp
+	title              *regexp.Regexp
+	subtitle           *regexp.Regexp
+	bulletList         *regexp.Regexp
+	enumList           *regexp.Regexp
+	field              *regexp.Regexp
+	rolePrefix         *regexp.Regexp
+	roleArgument       *regexp.Regexp
+	roleSuffix         *regexp.Regexp
+	simpleReference    *regexp.Regexp
+	embeddedURI        *regexp.Regexp
+	classifier         *regexp.Regexp
+	option             *regexp.Regexp
+	optionMarker       *regexp.Regexp
+	gridTableTop       *regexp.Regexp
+	gridTableSeparator *regexp.Regexp
+	simpleTableTop     *regexp.Regexp
+	simpleTableBorder  *regexp.Regexp
+	simpleTableSpan    *regexp.Regexp
 }
 
 // NewPatterns initializes and returns a new instance of Patterns with compiled regular expressions.
 func NewPatterns() *Patterns {
 	return &Patterns{
-		headingUnderline: regexp.MustCompile(`^[=\-~]+$`),
-		transBlock:       regexp.MustCompile(`{%\s*trans\s*%}(.*?){%\s*endtrans\s*%}`),
-		meta:             regexp.MustCompile(`^\.\.\s+meta::`),
-		directive:        regexp.MustCompile(`^\.\.\s+(\w+)::`),
-		codeBlock:        regexp.MustCompile(`^\.\.\s+code-block::`),
-		blockQuote:       regexp.MustCompile(`^(\s{4,})(.*?)(?:\s*--\s*(.*))?$`),
-		doctest:          regexp.MustCompile(`^>>> (.+)\n((?:[^>].*\n)*)`),
-		doctestContinue:  regexp.MustCompile(`^\.\.\.(.*$)`),
-		doctestOutput:    regexp.MustCompile(`^([^>][^>][^>].*)$`),
-		lineBlock:        regexp.MustCompile(`^\|(.*)$`),
-		comment:          regexp.MustCompile(`^\.\.\s(.*)$`),
-		title:            regexp.MustCompile(`^(={3,}|~{3,})\n(.+?)\n(?:={3,}|~{3,})$`),
-		subtitle:         regexp.MustCompile(`^(-{3,})\n(.+?)\n(?:-{3,})$`),
-		transition:       regexp.MustCompile(`^(\-{4,}|\={4,}|\*{4,})$`),
-		bulletList:       regexp.MustCompile(`^(\s*)([-*+])(\s+)(.+)$`),
-		enumList:         regexp.MustCompile(`^(\s*)(\d+|[a-zA-Z]|[ivxlcdm]+|[IVXLCDM]+|#)(\.\s+)(.+)$`),
-		emphasis:         regexp.MustCompile(`\*([^*]+)\*`),
-		strong:           regexp.MustCompile(`\*\*([^*]+)\*\*`),
+		adornment:          regexp.MustCompile(repeatedChar(adornmentChars)),
+		transBlock:         regexp.MustCompile(`{%\s*trans\s*%}(.*?){%\s*endtrans\s*%}`),
+		meta:               regexp.MustCompile(`^\.\.\s+meta::`),
+		directive:          regexp.MustCompile(`^\.\.\s+(\w+(?:[-_.:+]\w+)*)\s?::(?:\s|$)`),
+		codeBlock:          regexp.MustCompile(`^\.\.\s+code-block::`),
+		blockQuote:         regexp.MustCompile(`^(\s{4,})(.*?)(?:\s*--\s*(.*))?$`),
+		doctest:            regexp.MustCompile(`^>>>(?: (.*))?$`),
+		doctestContinue:    regexp.MustCompile(`^\.\.\.(?: (.*))?$`),
+		lineBlock:          regexp.MustCompile(`^\|(?:\s+(.*))?$`),
+		comment:            regexp.MustCompile(`^\.\.(?:\s+(.*))?$`),
+		footnote:           regexp.MustCompile(`^\.\.\s+\[(` + footnoteLabel + `)\](?:\s+|$)`),
+		footnoteReference:  regexp.MustCompile(`^\[(` + footnoteLabel + `)\]_`),
+		target:             regexp.MustCompile("^\\.\\.\\s+_(?:`([^`]+)`|([^\\s`](?:.*?[^\\s\\\\])?)) ?:(?:\\s+|$)"),
+		anonymousTarget:    regexp.MustCompile(`^__(?:\s+|$)`),
+		indirectTarget:     regexp.MustCompile("(?s)^(?:`(.+)`|(" + simpleName + "))_$"),
+		standaloneURI:      regexp.MustCompile(`^(?i:(?:https?|ftps?|sftp|file|ircs?|ssh|git|telnet|wss?)://|mailto:|news:)[^\s<>]*[^\s<>.,:;!?'")\]}]`),
+		email:              regexp.MustCompile(`^[\w.+-]+@[\w-]+(?:\.[\w-]+)+`),
+		substitutionDef:    regexp.MustCompile(`^\.\.\s+\|([^\s|](?:[^|]*[^\s|\\])?)\|(?:\s+|$)`),
+		embeddedDirective:  regexp.MustCompile(`^(\w+(?:[-_.:+]\w+)*)\s?::(?:\s|$)`),
+		unicodeCode:        regexp.MustCompile(`(?i)^(?:(?:0x|x|\\x|U\+?|\\u)([0-9a-f]+)|&#x([0-9a-f]+);)$`),
+		unicodeComment:     regexp.MustCompile(`(?:^|[ \n])\.\. `),
+		title:              regexp.MustCompile(`^(={3,}|~{3,})\n(.+?)\n(?:={3,}|~{3,})$`),
+		subtitle:           regexp.MustCompile(`^(-{3,})\n(.+?)\n(?:-{3,})$`),
+		bulletList:         regexp.MustCompile(`^(\s*)([-*+•‣⁃])(?:(\s+)(.*))?$`),
+		enumList:           regexp.MustCompile(`^(\s*)(\(?)(\d+|[a-zA-Z]|[ivxlcdm]+|[IVXLCDM]+|#)([.)])(?:\s+(.*))?$`),
+		field:              regexp.MustCompile(`^:([^:\s](?:[^:]*[^:\s])?):(?:\s+(.*))?$`),
+		rolePrefix:         regexp.MustCompile("^:(" + simpleName + "):`"),
+		roleSuffix:         regexp.MustCompile("^:" + simpleName + ":"),
+		roleArgument:       regexp.MustCompile(`^(` + simpleName + `)\s*(?:\(\s*(` + simpleName + `)\s*\)\s*)?$`),
+		simpleReference:    regexp.MustCompile("^(" + simpleName + ")(__?)"),
+		embeddedURI:        regexp.MustCompile(`(?s)(?:^|\s+)<([^<>]+)>$`),
+		classifier:         regexp.MustCompile(`\s+:\s+`),
+		option:             regexp.MustCompile(`^(?:` + option + `)`),
+		gridTableTop:       regexp.MustCompile(`^\+-[-+]+-\+$`),
+		gridTableSeparator: regexp.MustCompile(`^\+=[=+]+=\+$`),
+		simpleTableTop:     regexp.MustCompile(`^=+( +=+)*$`),
+		simpleTableBorder:  regexp.MustCompile(`^=+[ =]*$`),
+		simpleTableSpan:    regexp.MustCompile(`^-[ -]*$`),
+		optionMarker:       regexp.MustCompile(`^((?:` + option + `)(?:, (?:` + option + `))*)(?:  +(.*)| ?$)`),
 	}
 }
@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// runRaw passes its content, or the contents of the file named by the
+// "file" option, through untouched to writers of the output formats given
+// as its argument, as in ".. raw:: html".
+func runRaw(ctx *DirectiveContext) ([]nodes.Node, error) {
+	p := ctx.parser
+	format := strings.ToLower(strings.Join(strings.Fields(ctx.Arguments[0]), " "))
+	file, hasFile := ctx.Options["file"]
+	var text string
+	switch {
+	case ctx.Options.Has("url"):
+		return nil, fmt.Errorf("The \"url\" option of the %q directive is not supported.", ctx.Name)
+	case hasFile && len(ctx.content) > 0:
+		return nil, fmt.Errorf("%q directive may not both specify an external file and have content.", ctx.Name)
+	case hasFile:
+		if !p.fileInsertion {
+			return nil, fmt.Errorf("File and URL access deactivated; ignoring \"file\" option in %q directive.", ctx.Name)
+		}
+		raw, err := os.ReadFile(p.sourceRelative(file))
+		if err != nil {
+			return nil, fmt.Errorf("Problems with %q directive path:\n%v.", ctx.Name, err)
+		}
+		if text, err = decodeText(raw, ctx.Options.Value("encoding")); err != nil {
+			return nil, fmt.Errorf("Problems with %q directive path:\n%v.", ctx.Name, err)
+		}
+	case len(ctx.content) > 0:
+		text = ctx.Content()
+	default:
+		return nil, fmt.Errorf("The %q directive is empty; content required.", ctx.Name)
+	}
+	return []nodes.Node{nodes.NewRawNode(format, text)}, nil
+}
@@ -0,0 +1,214 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// DirectiveFunc builds the nodes of a directive. An error is reported as a
+// diagnostic and the directive is kept as a literal block.
+type DirectiveFunc func(ctx *DirectiveContext) ([]nodes.Node, error)
+
+// Directive describes a directive: how its block divides into arguments,
+// options and content, and how it becomes nodes.
+type Directive struct {
+	RequiredArguments int
+	OptionalArguments int
+	// FinalArgumentWhitespace lets the last argument contain whitespace, so
+	// that the remaining text becomes one argument instead of an error.
+	FinalArgumentWhitespace bool
+	// HasContent allows a content block after the arguments and options.
+	HasContent bool
+	// Options lists the options the directive accepts. Any other option is
+	// an error.
+	Options OptionSpec
+	Run     DirectiveFunc
+}
+
+// DirectiveRegistry maps directive names to their handlers. Names are
+// matched ignoring case.
+type DirectiveRegistry struct {
+	directives map[string]Directive
+}
+
+// NewDirectiveRegistry creates a registry holding the standard docutils
+// directives.
+func NewDirectiveRegistry() *DirectiveRegistry {
+	r := &DirectiveRegistry{directives: make(map[string]Directive)}
+	for name, directive := range standardDirectives() {
+		r.Register(name, directive)
+	}
+	return r
+}
+
+// Register adds a directive to the registry, replacing any directive of the
+// same name.
+func (r *DirectiveRegistry) Register(name string, directive Directive) {
+	r.directives[strings.ToLower(name)] = directive
+}
+
+// Lookup returns the directive registered under name.
+func (r *DirectiveRegistry) Lookup(name string) (Directive, bool) {
+	directive, ok := r.directives[strings.ToLower(name)]
+	return directive, ok
+}
+
+// DirectiveContext is the parsed block of a directive, passed to its
+// DirectiveFunc.
+type DirectiveContext struct {
+	Name      string                 // Name is the directive name as written.
+	Arguments []string               // Arguments are the directive arguments.
+	Options   nodes.DirectiveOptions // Options are the converted option values.
+	Position  nodes.Position         // Position is where the directive starts.
+	// Substitution is the substitution name being defined when the
+	// directive is embedded in a substitution definition, as in
+	// ".. |logo| image:: logo.png", and empty otherwise.
+	Substitution string
+	parser       *Parser
+	line         sourceLine
+	content      []sourceLine
+	indent       int
+	definition   *substitution
+}
+
+// Content returns the content block as text, with its common indentation
+// removed.
+func (c *DirectiveContext) Content() string {
+	return joinLines(c.content)
+}
+
+// ParseContent parses the content block as reStructuredText.
+func (c *DirectiveContext) ParseContent() []nodes.Node {
+	return c.parser.parseNested(c.content, c.indent)
+}
+
+// Report records a diagnostic at the start of the directive.
+func (c *DirectiveContext) Report(level Level, format string, args ...interface{}) {
+	c.parser.report(level, c.Position, format, args...)
+}
+
+// Errorf returns an error about the directive in the docutils form.
+func (c *DirectiveContext) Errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("Error in %q directive:\n%s.", c.Name, fmt.Sprintf(format, args...))
+}
+
+// RequireContent returns an error if the directive has no content.
+func (c *DirectiveContext) RequireContent() error {
+	if len(c.content) == 0 {
+		return fmt.Errorf("Content block expected for the %q directive; none found.", c.Name)
+	}
+	return nil
+}
+
+// OptionConverter checks the value of a directive option and returns it in
+// normal form.
+type OptionConverter func(value string) (string, error)
+
+// OptionSpec maps the option names a directive accepts to their converters.
+type OptionSpec map[string]OptionConverter
+
+// with returns a copy of the spec with more options added.
+func (s OptionSpec) with(more OptionSpec) OptionSpec {
+	spec := make(OptionSpec, len(s)+len(more))
+	for name, converter := range s {
+		spec[name] = converter
+	}
+	for name, converter := range more {
+		spec[name] = converter
+	}
+	return spec
+}
+
+// Flag accepts an option without a value, such as ":linenos:".
+func Flag(value string) (string, error) {
+	if strings.TrimSpace(value) != "" {
+		return "", fmt.Errorf("no argument is permitted; %q supplied", value)
+	}
+	return "", nil
+}
+
+// Unchanged accepts any value, including none.
+func Unchanged(value string) (string, error) {
+	return value, nil
+}
+
+// UnchangedRequired accepts any value but requires one.
+func UnchangedRequired(value string) (string, error) {
+	if value == "" {
+		return "", fmt.Errorf("argument required but none supplied")
+	}
+	return value, nil
+}
+
+// NonNegativeInt accepts an integer of zero or more.
+func NonNegativeInt(value string) (string, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return "", fmt.Errorf("invalid literal for int(): %q", value)
+	}
+	if n < 0 {
+		return "", fmt.Errorf("negative value; must be positive or zero")
+	}
+	return fmt.Sprint(n), nil
+}
+
+// Integer accepts an integer, which may be negative.
+func Integer(value string) (string, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return "", fmt.Errorf("invalid literal for int(): %q", value)
+	}
+	return fmt.Sprint(n), nil
+}
+
+// LengthOrPercentage accepts a length such as "200px" or "50%".
+func LengthOrPercentage(value string) (string, error) {
+	length, err := nodes.ParseLength(value)
+	if err != nil {
+		return "", err
+	}
+	return length.String(), nil
+}
+
+// URI accepts a URI, removing the whitespace of one split over several
+// lines.
+func URI(value string) (string, error) {
+	if value == "" {
+		return "", fmt.Errorf("argument required but none supplied")
+	}
+	return strings.Join(strings.Fields(value), ""), nil
+}
+
+// ClassOption accepts a list of class names separated by whitespace and
+// returns them lowercased.
+func ClassOption(value string) (string, error) {
+	classes := strings.Fields(strings.ToLower(value))
+	if len(classes) == 0 {
+		return "", fmt.Errorf("argument required but none supplied")
+	}
+	return strings.Join(classes, " "), nil
+}
+
+// Choice returns a converter accepting one of the given values, ignoring
+// case.
+func Choice(choices ...string) OptionConverter {
+	return func(value string) (string, error) {
+		for _, choice := range choices {
+			if strings.EqualFold(strings.TrimSpace(value), choice) {
+				return choice, nil
+			}
+		}
+		quoted := make([]string, len(choices))
+		for i, choice := range choices {
+			quoted[i] = fmt.Sprintf("%q", choice)
+		}
+		list := quoted[len(quoted)-1]
+		if len(quoted) > 1 {
+			list = strings.Join(quoted[:len(quoted)-1], ", ") + " or " + list
+		}
+		return "", fmt.Errorf("%q unknown; choose from %s", value, list)
+	}
+}
@@ -0,0 +1,280 @@
+package parser
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// defaultRole is the role of interpreted text without an explicit role,
+// unless the document sets another with the default-role directive.
+const defaultRole = "title-reference"
+
+// Base URLs of the pep-reference and rfc-reference roles.
+const (
+	pepBaseURL = "https://peps.python.org/"
+	rfcBaseURL = "https://tools.ietf.org/html/"
+)
+
+// RoleFunc builds the nodes of interpreted text with a role. An error is
+// reported as a diagnostic and the markup is kept as plain text.
+type RoleFunc func(ctx *RoleContext) ([]nodes.Node, error)
+
+// Role describes an interpreted text role.
+type Role struct {
+	// Options lists the options a role derived from this one with the role
+	// directive may set, besides "class".
+	Options OptionSpec
+	Run     RoleFunc
+}
+
+// RoleRegistry maps interpreted text role names to their handlers. Names
+// are matched ignoring case.
+type RoleRegistry struct {
+	roles map[string]Role
+}
+
+// NewRoleRegistry creates a registry holding the standard docutils roles.
+func NewRoleRegistry() *RoleRegistry {
+	r := &RoleRegistry{roles: make(map[string]Role)}
+	for name, role := range standardRoles() {
+		r.Register(name, role)
+	}
+	return r
+}
+
+// Register adds a role to the registry, replacing any role of the same name.
+func (r *RoleRegistry) Register(name string, role Role) {
+	r.roles[strings.ToLower(name)] = role
+}
+
+// Lookup returns the role registered under name.
+func (r *RoleRegistry) Lookup(name string) (Role, bool) {
+	role, ok := r.roles[strings.ToLower(name)]
+	return role, ok
+}
+
+// RoleContext is interpreted text passed to its RoleFunc.
+type RoleContext struct {
+	Name     string                 // Name is the role name as written.
+	Text     string                 // Text is the interpreted text, backslash escapes removed.
+	Rawtext  string                 // Rawtext is the whole markup, role included.
+	Options  nodes.DirectiveOptions // Options are set by the role directive for derived roles.
+	Position nodes.Position         // Position is where the markup starts.
+	parser   *Parser
+}
+
+// Report records a diagnostic at the start of the markup.
+func (c *RoleContext) Report(level Level, format string, args ...interface{}) {
+	c.parser.report(level, c.Position, format, args...)
+}
+
+// standardRoles returns the docutils interpreted text roles.
+func standardRoles() map[string]Role {
+	title := Role{Run: runTitleReference}
+	pep := Role{Run: runPEPReference}
+	rfc := Role{Run: runRFCReference}
+	return map[string]Role{
+		"emphasis":        {Run: runEmphasisRole},
+		"strong":          {Run: runStrongRole},
+		"literal":         {Run: runLiteralRole},
+		"code":            {Options: OptionSpec{"language": UnchangedRequired}, Run: runCodeRole},
+		"subscript":       {Run: inlineRole(nodes.InlineSubscript)},
+		"sub":             {Run: inlineRole(nodes.InlineSubscript)},
+		"superscript":     {Run: inlineRole(nodes.InlineSuperscript)},
+		"sup":             {Run: inlineRole(nodes.InlineSuperscript)},
+		"title-reference": title,
+		"title":           title,
+		"t":               title,
+		"abbreviation":    {Run: inlineRole(nodes.InlineAbbreviation)},
+		"ab":              {Run: inlineRole(nodes.InlineAbbreviation)},
+		"pep-reference":   pep,
+		"pep":             pep,
+		"rfc-reference":   rfc,
+		"rfc":             rfc,
+		"raw":             {Options: OptionSpec{"format": UnchangedRequired}, Run: runRawRole},
+	}
+}
+
+func runEmphasisRole(ctx *RoleContext) ([]nodes.Node, error) {
+	return []nodes.Node{ctx.parser.processEmphasis(ctx.Text)}, nil
+}
+
+func runStrongRole(ctx *RoleContext) ([]nodes.Node, error) {
+	return []nodes.Node{ctx.parser.processStrong(ctx.Text)}, nil
+}
+
+func runLiteralRole(ctx *RoleContext) ([]nodes.Node, error) {
+	return []nodes.Node{nodes.NewLiteralNode(ctx.Text)}, nil
+}
+
+func runTitleReference(ctx *RoleContext) ([]nodes.Node, error) {
+	return []nodes.Node{nodes.NewInterpretedTextNode(defaultRole, ctx.Text)}, nil
+}
+
+// inlineRole returns a role producing inline text of the given kind.
+func inlineRole(kind nodes.InlineKind) RoleFunc {
+	return func(ctx *RoleContext) ([]nodes.Node, error) {
+		return []nodes.Node{nodes.NewInlineNode(kind, ctx.Text)}, nil
+	}
+}
+
+// runCodeRole marks inline code, in the language set by a derived role.
+func runCodeRole(ctx *RoleContext) ([]nodes.Node, error) {
+	code := nodes.NewInlineNode(nodes.InlineCode, ctx.Text)
+	code.SetLanguage(ctx.Options.Value("language"))
+	return []nodes.Node{code}, nil
+}
+
+// runRawRole passes text through to the writers of the format set by a
+// derived role. The raw role cannot be used directly.
+func runRawRole(ctx *RoleContext) ([]nodes.Node, error) {
+	if !ctx.Options.Has("format") {
+		return nil, fmt.Errorf("No format (Writer name) is associated with this role: %q.\n"+
+			"The \"raw\" role cannot be used directly.\n"+
+			"Instead, use the \"role\" directive to create a new role with an associated format.", ctx.Name)
+	}
+	raw := nodes.NewInlineNode(nodes.InlineRaw, ctx.Text)
+	raw.SetFormat(ctx.Options.Value("format"))
+	return []nodes.Node{raw}, nil
+}
+
+// runPEPReference links to a Python Enhancement Proposal by number.
+func runPEPReference(ctx *RoleContext) ([]nodes.Node, error) {
+	number, err := strconv.Atoi(ctx.Text)
+	if err != nil || number < 0 || number > 9999 {
+		return nil, fmt.Errorf("PEP number must be a number from 0 to 9999; %q is invalid.", ctx.Text)
+	}
+	link := nodes.NewLinkNode(fmt.Sprintf("PEP %d", number), fmt.Sprintf("%spep-%04d", pepBaseURL, number), "")
+	return []nodes.Node{link}, nil
+}
+
+// runRFCReference links to an RFC by number, optionally followed by an
+// anchor within it, as in "2822#section-3.4".
+func runRFCReference(ctx *RoleContext) ([]nodes.Node, error) {
+	text, anchor, _ := strings.Cut(ctx.Text, "#")
+	number, err := strconv.Atoi(text)
+	if err != nil || number < 1 {
+		return nil, fmt.Errorf("RFC number must be a number greater than or equal to 1; %q is invalid.", ctx.Text)
+	}
+	uri := fmt.Sprintf("%srfc%d", rfcBaseURL, number)
+	if anchor != "" {
+		uri += "#" + anchor
+	}
+	link := nodes.NewLinkNode(fmt.Sprintf("RFC %d", number), uri, "")
+	return []nodes.Node{link}, nil
+}
+
+// derivedRole returns a role that runs base with the options set by the role
+// directive, and gives the resulting nodes the classes of the new role.
+// Nodes without classes of their own are wrapped in a span holding them.
+// When the role is the base of another derived role, the options of the
+// outer role take precedence, and only the outermost role sets classes.
+func derivedRole(base Role, options nodes.DirectiveOptions) Role {
+	return Role{Options: base.Options, Run: func(ctx *RoleContext) ([]nodes.Node, error) {
+		outermost := !ctx.Options.Has("class")
+		merged := maps.Clone(options)
+		maps.Copy(merged, ctx.Options)
+		ctx.Options = merged
+		result, err := base.Run(ctx)
+		classes := merged.List("class")
+		if err != nil || !outermost || len(classes) == 0 {
+			return result, err
+		}
+		for i, node := range result {
+			if inline, ok := node.(*nodes.InlineNode); ok {
+				inline.SetClasses(append(inline.Classes(), classes...))
+				continue
+			}
+			span := nodes.NewInlineNode(nodes.InlineSpan, "")
+			span.SetClasses(classes)
+			span.AddChild(node)
+			result[i] = span
+		}
+		return result, nil
+	}}
+}
+
+// lookupRole returns the role of the given name, looking first among the
+// roles defined by the document.
+func (p *Parser) lookupRole(name string) (Role, bool) {
+	if role, ok := p.localRoles[strings.ToLower(name)]; ok {
+		return role, true
+	}
+	return p.roles.Lookup(name)
+}
+
+// interpret runs the role of interpreted text. Text without a role takes the
+// default role. On error the markup is reported and kept as plain text.
+func (p *Parser) interpret(name, text, rawtext string, pos nodes.Position) []nodes.Node {
+	if name == "" {
+		name = p.defaultRole
+	}
+	role, ok := p.lookupRole(name)
+	if !ok {
+		p.report(LevelError, pos, "Unknown interpreted text role %q.", name)
+		return []nodes.Node{nodes.NewTextNode(rawtext)}
+	}
+	ctx := &RoleContext{Name: name, Text: text, Rawtext: rawtext, Options: make(nodes.DirectiveOptions), Position: pos, parser: p}
+	result, err := role.Run(ctx)
+	if err != nil {
+		p.report(LevelError, pos, "%s", err)
+		return []nodes.Node{nodes.NewTextNode(rawtext)}
+	}
+	return result
+}
+
+// runRoleDirective defines a role for the rest of the document. The argument
+// names the new role and, in parentheses, an optional base role whose
+// behavior it derives. The new role gives its text the classes of the
+// "class" option, which defaults to the role name.
+func runRoleDirective(ctx *DirectiveContext) ([]nodes.Node, error) {
+	p := ctx.parser
+	matches := p.patterns.roleArgument.FindStringSubmatch(ctx.Arguments[0])
+	if matches == nil {
+		return nil, fmt.Errorf("%q is not a valid argument for the %q directive.", ctx.Arguments[0], ctx.Name)
+	}
+	name, baseName := strings.ToLower(matches[1]), matches[2]
+
+	// Without a base role, the text is only given classes
+	base := Role{Run: inlineRole(nodes.InlineSpan)}
+	if baseName != "" {
+		var ok bool
+		if base, ok = p.lookupRole(baseName); !ok {
+			return nil, fmt.Errorf("Unknown interpreted text role %q.", baseName)
+		}
+	}
+	options := make(nodes.DirectiveOptions)
+	for _, option := range slices.Sorted(maps.Keys(ctx.Options)) {
+		if _, ok := base.Options[option]; !ok && option != "class" {
+			return nil, ctx.Errorf("unknown option: %q", option)
+		}
+		options[option] = ctx.Options[option]
+	}
+	if !options.Has("class") {
+		options["class"], _ = ClassOption(name)
+	}
+	p.localRoles[name] = derivedRole(base, options)
+	return []nodes.Node{}, nil
+}
+
+// runDefaultRoleDirective sets the role of interpreted text without an
+// explicit role for the rest of the document. Without an argument it
+// restores the standard default role.
+func runDefaultRoleDirective(ctx *DirectiveContext) ([]nodes.Node, error) {
+	p := ctx.parser
+	if len(ctx.Arguments) == 0 {
+		p.defaultRole = defaultRole
+		return []nodes.Node{}, nil
+	}
+	name := ctx.Arguments[0]
+	if _, ok := p.lookupRole(name); !ok {
+		return nil, fmt.Errorf("Unknown interpreted text role %q.", name)
+	}
+	p.defaultRole = name
+	return []nodes.Node{}, nil
+}
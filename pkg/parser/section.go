@@ -0,0 +1,39 @@
+package parser
+
+import "github.com/go-i2p/go-rst/pkg/nodes"
+
+// nestSections groups a flat list of top-level body elements into sections.
+// Each heading starts a section holding the heading and everything up to the
+// next heading of the same or a higher level; deeper headings start
+// subsections. Elements before the first heading stay at the top level.
+func nestSections(body []nodes.Node) []nodes.Node {
+	result := make([]nodes.Node, 0, len(body))
+	open := make([]*nodes.SectionNode, 0)
+	add := func(node nodes.Node) {
+		if len(open) == 0 {
+			result = append(result, node)
+			return
+		}
+		open[len(open)-1].AddChild(node)
+		// Every enclosing section now extends to the end of node
+		for _, section := range open {
+			section.SetPosition(section.Start(), node.End())
+		}
+	}
+	for _, node := range body {
+		heading, ok := node.(*nodes.HeadingNode)
+		if !ok {
+			add(node)
+			continue
+		}
+		for len(open) > 0 && open[len(open)-1].Level() >= heading.Level() {
+			open = open[:len(open)-1]
+		}
+		section := nodes.NewSectionNode(heading.Level())
+		section.AddChild(heading)
+		section.SetPosition(heading.Start(), heading.End())
+		add(section)
+		open = append(open, section)
+	}
+	return result
+}
@@ -0,0 +1,266 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// simpleColumn is the range of characters of a simple table column, from
+// the start of its rule up to the space after it.
+type simpleColumn struct {
+	start, end int
+}
+
+// simpleTable holds the state of parsing the rows of a simple table.
+type simpleTable struct {
+	lines   []sourceLine
+	columns []simpleColumn // the columns given by the top border
+	rows    []*nodes.TableRowNode
+	starts  []int // the index of the first line of each row
+}
+
+// processSimpleTable parses a simple table, whose columns are given by rules
+// of "=" characters above and below it. A rule of the same form between rows
+// ends the header rows, and a rule of "-" characters below a row groups its
+// columns into cells spanning several columns. A row whose first column is
+// blank continues the row above it. A malformed table is reported and kept
+// as a literal block.
+func (p *Parser) processSimpleTable(r *lineReader) nodes.Node {
+	start := r.pos
+	width := utf8.RuneCountInString(r.peek().text)
+	message := ""
+	found, foundAt, end := 0, 0, -1
+	for i := start + 1; i < len(r.lines); i++ {
+		line := r.lines[i]
+		if !p.patterns.simpleTableBorder.MatchString(line.text) {
+			continue
+		}
+		if utf8.RuneCountInString(line.text) != width {
+			message, end = "Malformed table. Bottom/header table border does not match top border.", i
+			break
+		}
+		found, foundAt = found+1, i
+		if found == 2 || i == len(r.lines)-1 || r.lines[i+1].isBlank() {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		if found > 0 {
+			message, end = "Malformed table. No bottom table border found or no blank line after table bottom.", foundAt
+		} else {
+			message, end = "Malformed table. No bottom table border found.", len(r.lines)-1
+		}
+	}
+	lines := r.lines[start : end+1]
+	r.pos = end + 1
+
+	var table nodes.Node
+	if message == "" {
+		table, message = p.parseSimpleTable(lines)
+	}
+	if table == nil {
+		p.report(LevelError, lines[0].position(0), "%s", message)
+		table = nodes.NewCodeNode("", joinLines(lines), false)
+	}
+	if !r.eof() && !r.peek().isBlank() {
+		p.report(LevelWarning, r.peek().position(0), "Blank line required after table.")
+	}
+	return table
+}
+
+// isAdornment reports whether the top border of a one-column simple table
+// at the current line is rather a transition or a section title overline,
+// which take precedence: it is followed by a blank line, or by a line of
+// text and an underline matching it.
+func isAdornment(r *lineReader) bool {
+	border := r.peek()
+	if strings.Contains(border.text, " ") {
+		return false
+	}
+	next, ok := r.peekAt(1)
+	if !ok || next.isBlank() {
+		return true
+	}
+	underline, ok := r.peekAt(2)
+	return ok && underline.text == border.text
+}
+
+// parseSimpleTable parses the lines of a simple table, from its top border
+// to its bottom border. It returns nil and a message if the table is
+// malformed.
+func (p *Parser) parseSimpleTable(lines []sourceLine) (nodes.Node, string) {
+	t := &simpleTable{lines: make([]sourceLine, len(lines))}
+	copy(t.lines, lines)
+	headerSeparator := 0
+	for i := 1; i < len(lines)-1; i++ {
+		if !p.patterns.simpleTableBorder.MatchString(lines[i].text) {
+			continue
+		}
+		if headerSeparator > 0 {
+			return nil, fmt.Sprintf("Malformed table. Multiple head/body row separators (table lines %d and %d); only one allowed.",
+				headerSeparator+1, i+1)
+		}
+		headerSeparator = i
+		t.lines[i].text = strings.ReplaceAll(lines[i].text, "=", "-")
+	}
+	last := len(lines) - 1
+	t.lines[0].text = strings.ReplaceAll(lines[0].text, "=", "-")
+	t.lines[last].text = strings.ReplaceAll(lines[last].text, "=", "-")
+	t.columns = t.parseColumns(0)
+
+	// Rows start at a line with text in the first column and end at the
+	// next such line or at a rule of "-" characters.
+	first := t.columns[0]
+	start, textFound := 1, false
+	for offset := 1; offset < len(t.lines); offset++ {
+		line := t.lines[offset]
+		switch {
+		case p.patterns.simpleTableSpan.MatchString(line.text):
+			if message := p.parseSimpleRow(t, start, offset, offset); message != "" {
+				return nil, message
+			}
+			start, textFound = offset+1, false
+		case strings.TrimSpace(runeSlice(line.text, first.start, first.end)) != "":
+			if textFound && offset != start {
+				if message := p.parseSimpleRow(t, start, offset, -1); message != "" {
+					return nil, message
+				}
+			}
+			start, textFound = offset, true
+		case !textFound:
+			start = offset + 1
+		}
+	}
+
+	table := nodes.NewTableNode()
+	widths := make([]int, len(t.columns))
+	for i, column := range t.columns {
+		widths[i] = column.end - column.start
+	}
+	table.SetColumnWidths(widths)
+	for i, row := range t.rows {
+		if t.starts[i] < headerSeparator {
+			table.AddHeaderRow(row)
+		} else {
+			table.AddBodyRow(row)
+		}
+	}
+	return table, ""
+}
+
+// parseSimpleRow parses the row on lines start to end of the table. If
+// spanLine is not negative, it is the index of the rule below the row, which
+// gives the columns of its cells.
+func (p *Parser) parseSimpleRow(t *simpleTable, start, end, spanLine int) string {
+	if start == end && spanLine < 0 {
+		return ""
+	}
+	columns := append([]simpleColumn(nil), t.columns...)
+	if spanLine >= 0 {
+		columns = t.parseColumns(spanLine)
+		if columns[len(columns)-1].end != t.columns[len(t.columns)-1].end {
+			return fmt.Sprintf("Malformed table. Column span incomplete in table line %d.", spanLine+1)
+		}
+	}
+	if message := t.checkColumns(start, end, columns); message != "" {
+		return message
+	}
+
+	row := nodes.NewTableRowNode()
+	col := 0
+	for _, column := range columns {
+		if col >= len(t.columns) || column.start != t.columns[col].start {
+			return fmt.Sprintf("Malformed table. Column span alignment problem in table line %d.", start+1)
+		}
+		span := 1
+		for column.end != t.columns[col].end {
+			col, span = col+1, span+1
+			if col >= len(t.columns) {
+				return fmt.Sprintf("Malformed table. Column span alignment problem in table line %d.", start+1)
+			}
+		}
+		col++
+
+		cell := nodes.NewTableCellNode()
+		cell.SetSpan(1, span)
+		body, indent := cellLines(t.lines[start:end], column.start, column.end)
+		if start < end {
+			cell.SetPosition(spanLines(body))
+		}
+		for _, child := range p.parseNested(body, column.start+indent) {
+			cell.AddChild(child)
+		}
+		row.AppendCell(cell)
+	}
+	if start < end {
+		row.SetPosition(spanLines(t.lines[start:end]))
+	}
+	t.rows = append(t.rows, row)
+	t.starts = append(t.starts, start)
+	return ""
+}
+
+// parseColumns returns the columns given by the rules on a line of the
+// table. The last column of a row extends to the end of the table.
+func (t *simpleTable) parseColumns(line int) []simpleColumn {
+	text := []rune(t.lines[line].text)
+	columns := make([]simpleColumn, 0)
+	for end := 0; ; {
+		begin := indexRune(text, '-', end)
+		if begin < 0 {
+			break
+		}
+		if end = indexRune(text, ' ', begin); end < 0 {
+			end = len(text)
+		}
+		columns = append(columns, simpleColumn{begin, end})
+	}
+	if t.columns != nil {
+		columns[len(columns)-1].end = t.columns[len(t.columns)-1].end
+	}
+	return columns
+}
+
+// checkColumns checks that no text of lines start to end lies between
+// columns. Text may extend past the end of the last column, which then
+// widens the last column of the table.
+func (t *simpleTable) checkColumns(start, end int, columns []simpleColumn) string {
+	lastColumn := len(columns) - 1
+	for i, column := range columns {
+		for offset, line := range t.lines[start:end] {
+			text := []rune(line.text)
+			switch {
+			case i == lastColumn:
+				if column.end < len(text) && strings.TrimSpace(string(text[column.end:])) != "" {
+					columns[i].end = column.start + len([]rune(strings.TrimRight(string(text[column.start:]), " ")))
+					main := &t.columns[len(t.columns)-1]
+					main.end = max(main.end, columns[i].end)
+					column = columns[i]
+				}
+			case strings.TrimSpace(runeSlice(line.text, column.end, columns[i+1].start)) != "":
+				return fmt.Sprintf("Malformed table. Text in column margin in table line %d.", start+offset+1)
+			}
+		}
+	}
+	return ""
+}
+
+// runeSlice returns the characters from start up to end of text, clipped to
+// its length.
+func runeSlice(text string, start, end int) string {
+	return text[runeOffset(text, start):runeOffset(text, end)]
+}
+
+// indexRune returns the index of the first r in text at or after from, or -1.
+func indexRune(text []rune, r rune, from int) int {
+	for i := from; i < len(text); i++ {
+		if text[i] == r {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,278 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// substitution is a substitution definition: the inline nodes that replace
+// references to name, and whether the whitespace around a reference is
+// removed.
+type substitution struct {
+	name         string
+	nodes        []nodes.Node
+	ltrim, rtrim bool
+}
+
+// processSubstitutionDef parses a substitution definition such as
+// ".. |version| replace:: 1.2". The definition embeds a directive, run with
+// the substitution name in its context, whose nodes must all be inline. The
+// definition is recorded for resolveSubstitutions and adds nothing to the
+// tree. A definition without a directive, or one whose directive fails, is
+// reported and kept as a literal block.
+func (p *Parser) processSubstitutionDef(r *lineReader) []nodes.Node {
+	start := r.pos
+	line := r.next()
+	marker := p.patterns.substitutionDef.FindStringSubmatchIndex(line.text)
+	name := strings.Join(strings.Fields(line.text[marker[2]:marker[3]]), " ")
+	rest := line.slice(marker[1])
+	literal := func() []nodes.Node {
+		return []nodes.Node{nodes.NewCodeNode("", joinLines(r.lines[start:r.pos]), false)}
+	}
+
+	directive := p.patterns.embeddedDirective.FindStringSubmatchIndex(rest.text)
+	if directive == nil {
+		body, _ := r.firstKnownIndented(rest)
+		p.checkExplicitFinish(r)
+		if len(trimBlankLines(body)) == 0 {
+			p.report(LevelWarning, line.position(0), "Substitution definition %q missing contents.", name)
+		} else {
+			p.report(LevelError, line.position(0), "Substitution definition %q empty or invalid.", name)
+		}
+		return literal()
+	}
+
+	definition := &substitution{name: name}
+	result, ok := p.runDirectiveBlock(r, start, rest, rest.text[directive[2]:directive[3]], directive[1], definition)
+	if !ok {
+		return result
+	}
+	for _, node := range result {
+		if !isInlineNode(node) {
+			p.report(LevelError, line.position(0), "Substitution definition %q may only contain inline elements.", name)
+			return literal()
+		}
+		if !node.Start().IsValid() {
+			node.SetPosition(spanLines(r.lines[start:r.pos]))
+		}
+	}
+	definition.nodes = result
+
+	key := normalizeName(name)
+	if _, ok := p.substitutions[key]; ok {
+		p.report(LevelError, line.position(0), "Duplicate substitution definition name: %q.", name)
+	}
+	p.substitutions[key] = definition
+	return []nodes.Node{}
+}
+
+// isInlineNode reports whether node may appear within a paragraph.
+func isInlineNode(node nodes.Node) bool {
+	switch node.(type) {
+	case *nodes.TextNode, *nodes.EmphasisNode, *nodes.StrongNode, *nodes.LiteralNode,
+		*nodes.InterpretedTextNode, *nodes.InlineNode, *nodes.LinkNode,
+		*nodes.SubstitutionReferenceNode, *nodes.FootnoteReferenceNode, *nodes.ImageNode:
+		return true
+	}
+	return false
+}
+
+// lookupSubstitution returns the definition of the substitution name. Names
+// match ignoring case and whitespace. Definitions in the document take
+// precedence over those set with SetSubstitution.
+func (p *Parser) lookupSubstitution(name string) (*substitution, bool) {
+	key := normalizeName(name)
+	if definition, ok := p.substitutions[key]; ok {
+		return definition, true
+	}
+	text, ok := p.appSubstitutions[key]
+	if !ok {
+		return nil, false
+	}
+	definition := &substitution{name: name, nodes: p.parseInline(text, nil)}
+	p.substitutions[key] = definition
+	return definition, true
+}
+
+// resolveSubstitutions gives each substitution reference in list a copy of
+// the nodes of its definition as children, expanding references within definitions
+// too. active holds the definitions being expanded, so that circular ones
+// are caught. Undefined substitutions are reported and the reference is
+// left empty.
+func (p *Parser) resolveSubstitutions(list []nodes.Node, active map[*substitution]bool) {
+	for i, node := range list {
+		ref, ok := node.(*nodes.SubstitutionReferenceNode)
+		if !ok {
+			p.resolveSubstitutions(node.Children(), active)
+			continue
+		}
+		if len(ref.Children()) > 0 {
+			// Already resolved as part of another definition
+			continue
+		}
+		definition, ok := p.lookupSubstitution(ref.Name())
+		if !ok {
+			p.report(LevelError, ref.Start(), "Undefined substitution referenced: %q.", ref.Name())
+			continue
+		}
+		if active[definition] {
+			p.report(LevelError, ref.Start(), "Circular substitution definition referenced: %q.", ref.Name())
+			continue
+		}
+		active[definition] = true
+		p.resolveSubstitutions(definition.nodes, active)
+		delete(active, definition)
+
+		// Later passes give the nodes IDs and links, so they are not shared
+		for _, child := range definition.nodes {
+			ref.AddChild(nodes.CopyInline(child))
+		}
+		if i > 0 && definition.ltrim {
+			if text, ok := list[i-1].(*nodes.TextNode); ok {
+				text.SetContent(strings.TrimRight(text.Content(), " \t\n"))
+			}
+		}
+		if i+1 < len(list) && definition.rtrim {
+			if text, ok := list[i+1].(*nodes.TextNode); ok {
+				text.SetContent(strings.TrimLeft(text.Content(), " \t\n"))
+			}
+		}
+	}
+}
+
+// requireSubstitution returns an error unless the directive is embedded in
+// a substitution definition.
+func requireSubstitution(ctx *DirectiveContext) error {
+	if ctx.definition == nil {
+		return fmt.Errorf("Invalid context: the %q directive can only be used within a substitution definition.", ctx.Name)
+	}
+	return nil
+}
+
+// runReplace replaces the substitution with the inline content of its single
+// paragraph, as in ".. |RST| replace:: reStructuredText".
+func runReplace(ctx *DirectiveContext) ([]nodes.Node, error) {
+	if err := requireSubstitution(ctx); err != nil {
+		return nil, err
+	}
+	if err := ctx.RequireContent(); err != nil {
+		return nil, err
+	}
+	content := ctx.ParseContent()
+	if len(content) == 1 {
+		if paragraph, ok := content[0].(*nodes.ParagraphNode); ok {
+			return paragraph.Children(), nil
+		}
+	}
+	return nil, ctx.Errorf("may contain a single paragraph only")
+}
+
+// runUnicode replaces the substitution with the characters of its argument.
+// Character codes may be decimal or hexadecimal ("0x262E", "U+262E",
+// "&#x262E;"); other text is kept as is, and ".. " starts a comment. The
+// trim options remove the whitespace around references.
+func runUnicode(ctx *DirectiveContext) ([]nodes.Node, error) {
+	if err := requireSubstitution(ctx); err != nil {
+		return nil, err
+	}
+	text := ctx.Arguments[0]
+	if comment := ctx.parser.patterns.unicodeComment.FindStringIndex(text); comment != nil {
+		text = text[:comment[0]]
+	}
+	var b strings.Builder
+	for _, code := range strings.Fields(text) {
+		char, err := ctx.parser.unicodeCode(code)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid character code: %s\n%v", code, err)
+		}
+		b.WriteString(char)
+	}
+	trim := ctx.Options.Has("trim")
+	ctx.definition.ltrim = trim || ctx.Options.Has("ltrim")
+	ctx.definition.rtrim = trim || ctx.Options.Has("rtrim")
+	return []nodes.Node{nodes.NewTextNode(b.String())}, nil
+}
+
+// unicodeCode converts a character code to the character. Text that is not
+// a code is returned unchanged.
+func (p *Parser) unicodeCode(code string) (string, error) {
+	base, digits := 10, code
+	if strings.Trim(code, "0123456789") != "" {
+		matches := p.patterns.unicodeCode.FindStringSubmatch(code)
+		if matches == nil {
+			return code, nil
+		}
+		base, digits = 16, matches[1]+matches[2]
+	}
+	value, err := strconv.ParseUint(digits, base, 32)
+	if err != nil || value > utf8.MaxRune {
+		return "", fmt.Errorf("code too large")
+	}
+	return string(rune(value)), nil
+}
+
+// runDate replaces the substitution with the current date, in the strftime
+// format of the argument, "%Y-%m-%d" by default. For reproducible builds the
+// time is taken from SOURCE_DATE_EPOCH when it is set.
+func runDate(ctx *DirectiveContext) ([]nodes.Node, error) {
+	if err := requireSubstitution(ctx); err != nil {
+		return nil, err
+	}
+	format := "%Y-%m-%d"
+	if len(ctx.Arguments) > 0 {
+		format = ctx.Arguments[0]
+	}
+	now := time.Now()
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		seconds, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid SOURCE_DATE_EPOCH: %q.", epoch)
+		}
+		now = time.Unix(seconds, 0).UTC()
+	}
+	return []nodes.Node{nodes.NewTextNode(strftime(format, now))}, nil
+}
+
+// strftimeLayouts maps strftime conversions to Go time layouts.
+var strftimeLayouts = map[byte]string{
+	'a': "Mon", 'A': "Monday", 'b': "Jan", 'B': "January", 'c': "Mon Jan _2 15:04:05 2006",
+	'd': "02", 'e': "_2", 'H': "15", 'I': "03", 'm': "01", 'M': "04", 'p': "PM", 'S': "05",
+	'x': "01/02/06", 'X': "15:04:05", 'y': "06", 'Y': "2006", 'z': "-0700", 'Z': "MST",
+}
+
+// strftime formats t as the C function of the same name does. Unknown
+// conversions are kept as written.
+func strftime(format string, t time.Time) string {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			b.WriteByte(format[i])
+			continue
+		}
+		i++
+		switch c := format[i]; c {
+		case '%':
+			b.WriteByte('%')
+		case 'f':
+			fmt.Fprintf(&b, "%06d", t.Nanosecond()/1000)
+		case 'j':
+			fmt.Fprintf(&b, "%03d", t.YearDay())
+		case 'w':
+			fmt.Fprintf(&b, "%d", t.Weekday())
+		default:
+			if layout, ok := strftimeLayouts[c]; ok {
+				b.WriteString(t.Format(layout))
+			} else {
+				b.WriteByte('%')
+				b.WriteByte(c)
+			}
+		}
+	}
+	return b.String()
+}
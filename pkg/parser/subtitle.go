@@ -1 +1,16 @@
 package parser
+
+import "github.com/go-i2p/go-rst/pkg/nodes"
+
+// promoteSubtitle turns the only subsection following the document title
+// into part of the document body, its heading becoming a SubtitleNode. The
+// subsection must directly follow the title, ignoring comments and metadata,
+// and be the last element of the document.
+func (p *Parser) promoteSubtitle(body []nodes.Node, title int) []nodes.Node {
+	i, section := loneSection(body, title+1)
+	if section == nil {
+		return body
+	}
+	subtitle := nodes.NewSubtitleNode(section.Heading().Content())
+	return unwrapSection(body, i, section, subtitle)
+}
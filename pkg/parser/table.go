@@ -1 +1,278 @@
 package parser
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// gridCell is the area of a grid table cell, given by the rows and columns
+// of its borders.
+type gridCell struct {
+	top, left, bottom, right int
+}
+
+// gridTable holds the state of parsing the layout of a grid table. It
+// follows the docutils algorithm: starting from the top left corner, each
+// cell is traced clockwise along its borders, and the top right and bottom
+// left corners of every cell found are queued as the origins of further
+// cells.
+type gridTable struct {
+	block         [][]rune
+	bottom, right int   // index of the last row and column of the block
+	done          []int // per column, the last row covered by a cell so far
+	cells         []gridCell
+}
+
+// processGridTable parses a grid table, whose cells are drawn with "+", "-"
+// and "|" characters. An optional separator line drawn with "=" ends the
+// header rows. Cells may span rows and columns and hold any body elements.
+// A malformed table is reported and kept as a literal block.
+func (p *Parser) processGridTable(r *lineReader) nodes.Node {
+	lines := make([]sourceLine, 0)
+	for !r.eof() {
+		line := r.peek()
+		if line.isBlank() || !strings.ContainsAny(line.text[:1], "+|") {
+			break
+		}
+		lines = append(lines, r.next())
+	}
+	// The table ends at its last border line; anything after it is left
+	// to be parsed as text.
+	end := len(lines)
+	for end > 1 && !p.patterns.gridTableTop.MatchString(lines[end-1].text) {
+		end--
+	}
+	if end > 2 {
+		r.pos -= len(lines) - end
+		lines = lines[:end]
+	}
+
+	table, message := p.parseGridTable(lines)
+	if table == nil {
+		p.report(LevelError, lines[0].position(0), "%s", message)
+		table = nodes.NewCodeNode("", joinLines(lines), false)
+	}
+	if !r.eof() && !r.peek().isBlank() {
+		p.report(LevelWarning, r.peek().position(0), "Blank line required after table.")
+	}
+	return table
+}
+
+// parseGridTable parses the lines of a grid table. It returns nil and a
+// message if the table is malformed.
+func (p *Parser) parseGridTable(lines []sourceLine) (nodes.Node, string) {
+	width := utf8.RuneCountInString(lines[0].text)
+	block := make([][]rune, len(lines))
+	headerSeparator := 0
+	for i, line := range lines {
+		block[i] = []rune(line.text)
+		last := block[i][len(block[i])-1]
+		if len(block[i]) != width || (last != '+' && last != '|') {
+			return nil, "Malformed table. Text in column margin or right edge inconsistent."
+		}
+		if p.patterns.gridTableSeparator.MatchString(line.text) {
+			if headerSeparator > 0 {
+				return nil, "Malformed table. Multiple head/body row separators; only one allowed."
+			}
+			if i == 0 || i == len(lines)-1 {
+				return nil, "Malformed table. The head/body row separator may not be the first or last line of the table."
+			}
+			headerSeparator = i
+			block[i] = []rune(strings.ReplaceAll(line.text, "=", "-"))
+		}
+	}
+	if len(lines) < 3 || !p.patterns.gridTableTop.MatchString(lines[len(lines)-1].text) {
+		return nil, "Malformed table. Missing bottom border."
+	}
+
+	grid := &gridTable{block: block, bottom: len(block) - 1, right: width - 1, done: make([]int, width)}
+	if !grid.parse() {
+		return nil, "Malformed table. Parse incomplete."
+	}
+
+	// Row and column boundaries are the borders of all cells.
+	rowSeps, colSeps := map[int]int{}, map[int]int{}
+	for _, cell := range grid.cells {
+		rowSeps[cell.top], rowSeps[cell.bottom] = 0, 0
+		colSeps[cell.left], colSeps[cell.right] = 0, 0
+	}
+	rowBorders, colBorders := indexBorders(rowSeps), indexBorders(colSeps)
+	headerRows, ok := rowSeps[headerSeparator]
+	if headerSeparator > 0 && !ok {
+		return nil, "Malformed table. The head/body row separator must span the table."
+	}
+
+	table := nodes.NewTableNode()
+	widths := make([]int, len(colBorders)-1)
+	for i := range widths {
+		widths[i] = colBorders[i+1] - colBorders[i] - 1
+	}
+	table.SetColumnWidths(widths)
+	rows := make([]*nodes.TableRowNode, len(rowBorders)-1)
+	for i := range rows {
+		rows[i] = nodes.NewTableRowNode()
+		rows[i].SetPosition(spanLines(lines[rowBorders[i] : rowBorders[i+1]+1]))
+		if i < headerRows {
+			table.AddHeaderRow(rows[i])
+		} else {
+			table.AddBodyRow(rows[i])
+		}
+	}
+	// Cells were found in order of their top left corners.
+	for _, area := range grid.cells {
+		cell := nodes.NewTableCellNode()
+		cell.SetSpan(rowSeps[area.bottom]-rowSeps[area.top], colSeps[area.right]-colSeps[area.left])
+		cell.SetPosition(spanLines(lines[area.top : area.bottom+1]))
+		body, indent := cellLines(lines[area.top+1:area.bottom], area.left+1, area.right)
+		for _, child := range p.parseNested(body, area.left+1+indent) {
+			cell.AddChild(child)
+		}
+		rows[rowSeps[area.top]].AppendCell(cell)
+	}
+	return table, ""
+}
+
+// indexBorders sorts the borders recorded as the keys of seps and sets each
+// to its index. It returns the sorted borders.
+func indexBorders(seps map[int]int) []int {
+	borders := make([]int, 0, len(seps))
+	for border := range seps {
+		borders = append(borders, border)
+	}
+	sort.Ints(borders)
+	for i, border := range borders {
+		seps[border] = i
+	}
+	return borders
+}
+
+// cellLines extracts the text between columns left and right of lines, with
+// the indentation common to all lines removed. It returns the lines and the
+// indentation removed.
+func cellLines(lines []sourceLine, left, right int) ([]sourceLine, int) {
+	cell := make([]sourceLine, len(lines))
+	indent := -1
+	for i, line := range lines {
+		from, to := runeOffset(line.text, left), runeOffset(line.text, right)
+		cell[i] = line.slice(from)
+		cell[i].text = strings.TrimRight(cell[i].text[:to-from], " ")
+		if !cell[i].isBlank() && (indent < 0 || cell[i].indent() < indent) {
+			indent = cell[i].indent()
+		}
+	}
+	indent = max(indent, 0)
+	for i := range cell {
+		cell[i] = cell[i].slice(indent)
+	}
+	return cell, indent
+}
+
+// runeOffset returns the byte offset of the nth character of text.
+func runeOffset(text string, n int) int {
+	for i := range text {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(text)
+}
+
+// parse finds all cells of the table. It reports false if the cells do not
+// cover the whole table.
+func (g *gridTable) parse() bool {
+	for i := range g.done {
+		g.done[i] = -1
+	}
+	corners := []gridCell{{}}
+	for len(corners) > 0 {
+		top, left := corners[0].top, corners[0].left
+		corners = corners[1:]
+		if top == g.bottom || left == g.right || top <= g.done[left] {
+			continue
+		}
+		bottom, right, ok := g.scanCell(top, left)
+		if !ok {
+			continue
+		}
+		for col := left; col < right; col++ {
+			if g.done[col] != top-1 {
+				return false
+			}
+			g.done[col] = bottom - 1
+		}
+		g.cells = append(g.cells, gridCell{top, left, bottom, right})
+		corners = append(corners, gridCell{top: top, left: right}, gridCell{top: bottom, left: left})
+		sort.Slice(corners, func(i, j int) bool {
+			if corners[i].top != corners[j].top {
+				return corners[i].top < corners[j].top
+			}
+			return corners[i].left < corners[j].left
+		})
+	}
+	for _, last := range g.done[:g.right] {
+		if last != g.bottom-1 {
+			return false
+		}
+	}
+	return true
+}
+
+// scanCell traces the cell whose top left corner is at top, left, and
+// returns its bottom right corner. It follows the top border to the right
+// and tries each "+" on it as the top right corner.
+func (g *gridTable) scanCell(top, left int) (int, int, bool) {
+	line := g.block[top]
+	for right := left + 1; right <= g.right; right++ {
+		switch line[right] {
+		case '+':
+			if bottom, ok := g.scanDown(top, left, right); ok {
+				return bottom, right, true
+			}
+		case '-':
+		default:
+			return 0, 0, false
+		}
+	}
+	return 0, 0, false
+}
+
+// scanDown follows the right border of a cell down from its top right
+// corner and tries each "+" on it as the bottom right corner.
+func (g *gridTable) scanDown(top, left, right int) (int, bool) {
+	for bottom := top + 1; bottom <= g.bottom; bottom++ {
+		switch g.block[bottom][right] {
+		case '+':
+			if g.scanLeft(top, left, bottom, right) {
+				return bottom, true
+			}
+		case '|':
+		default:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// scanLeft checks the bottom border of a cell from right to left, then its
+// left border from bottom to top.
+func (g *gridTable) scanLeft(top, left, bottom, right int) bool {
+	line := g.block[bottom]
+	for col := right - 1; col > left; col-- {
+		if line[col] != '+' && line[col] != '-' {
+			return false
+		}
+	}
+	if line[left] != '+' {
+		return false
+	}
+	for row := bottom - 1; row > top; row-- {
+		if g.block[row][left] != '+' && g.block[row][left] != '|' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,200 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/go-i2p/go-rst/pkg/nodes"
+)
+
+// processTarget parses a hyperlink target (".. _name: URI"). The link block
+// after the name is a URI, whose whitespace is removed, the name of another
+// target followed by "_", or empty for an internal target. Anonymous targets
+// are written ".. __: URI" or "__ URI".
+func (p *Parser) processTarget(r *lineReader) *nodes.TargetNode {
+	line := r.next()
+	name, end := "", len(p.patterns.anonymousTarget.FindString(line.text))
+	if marker := p.patterns.target.FindStringSubmatchIndex(line.text); marker != nil {
+		if marker[2] >= 0 {
+			name = line.text[marker[2]:marker[3]]
+		} else if name = line.text[marker[4]:marker[5]]; name == "_" {
+			name = ""
+		}
+		end = marker[1]
+	}
+	body, _ := r.firstKnownIndented(line.slice(end))
+	p.checkExplicitFinish(r)
+
+	target := nodes.NewTargetNode(normalizeName(unescape(name)))
+	block := strings.TrimSpace(joinLines(body))
+	if matches := p.patterns.indirectTarget.FindStringSubmatch(block); matches != nil {
+		target.SetRefName(normalizeName(unescape(matches[1] + matches[2])))
+	} else if block != "" {
+		target.SetURI(strings.Join(strings.Fields(unescape(block)), ""))
+	}
+	return target
+}
+
+// targetTable holds the named hyperlink targets of a document.
+type targetTable struct {
+	p          *Parser
+	named      map[string]*nodes.TargetNode
+	duplicates map[string]bool
+	// ends maps indirect targets to the target at the end of their chain,
+	// or nil if the chain is broken
+	ends     map[*nodes.TargetNode]*nodes.TargetNode
+	visiting map[*nodes.TargetNode]bool
+}
+
+// add records a named target. A second target of the same name makes the
+// name ambiguous, unless both refer to the same URI.
+func (t *targetTable) add(target *nodes.TargetNode) {
+	existing, ok := t.named[target.Name()]
+	if !ok {
+		t.named[target.Name()] = target
+		return
+	}
+	if existing.URI() != "" && existing.URI() == target.URI() {
+		return
+	}
+	t.p.report(LevelWarning, target.Start(), "Duplicate explicit target name: %q.", target.Name())
+	t.duplicates[target.Name()] = true
+}
+
+// follow returns the target at the end of the chain of indirect targets
+// starting at target, or nil if the chain is broken, which is reported once.
+func (t *targetTable) follow(target *nodes.TargetNode) *nodes.TargetNode {
+	if target.RefName() == "" {
+		return target
+	}
+	if end, ok := t.ends[target]; ok {
+		return end
+	}
+	t.visiting[target] = true
+	defer delete(t.visiting, target)
+
+	var end *nodes.TargetNode
+	next, ok := t.named[target.RefName()]
+	switch {
+	case !ok:
+		t.p.report(LevelError, target.Start(), "Indirect hyperlink target %q refers to target %q, which does not exist.", target.Name(), target.RefName())
+	case t.duplicates[target.RefName()]:
+		t.p.report(LevelError, target.Start(), "Indirect hyperlink target %q refers to target %q, which is a duplicate, and cannot be used as a unique reference.", target.Name(), target.RefName())
+	case t.visiting[next]:
+		t.p.report(LevelError, target.Start(), "Indirect hyperlink target %q refers to target %q, which is a circular reference.", target.Name(), target.RefName())
+	default:
+		end = t.follow(next)
+	}
+	t.ends[target] = end
+	return end
+}
+
+// resolve points link at the end of the chain starting at target: its URI,
+// or the ID of an internal target or section for the renderers to link
+// within the document.
+func (t *targetTable) resolve(link *nodes.LinkNode, target *nodes.TargetNode) {
+	end := t.follow(target)
+	switch {
+	case end == nil:
+	case end.URI() != "":
+		link.SetURL(end.URI())
+	default:
+		link.SetRefName(end.Name())
+		link.SetRefID(end.ID())
+	}
+}
+
+// titleTarget is a section or document title, which is an implicit target
+// named by its text.
+type titleTarget struct {
+	node nodes.Node
+	text string
+}
+
+// resolveReferences resolves the hyperlink references of the document. Named
+// references match explicit targets, the targets defined by references with
+// an embedded URI, and section titles; anonymous references match anonymous
+// targets in order. Unknown, ambiguous and mismatched references are
+// reported and left unresolved.
+func (p *Parser) resolveReferences(doc []nodes.Node) {
+	table := &targetTable{
+		p:          p,
+		named:      make(map[string]*nodes.TargetNode),
+		duplicates: make(map[string]bool),
+		ends:       make(map[*nodes.TargetNode]*nodes.TargetNode),
+		visiting:   make(map[*nodes.TargetNode]bool),
+	}
+	var anonymous []*nodes.TargetNode
+	var links, anonymousLinks []*nodes.LinkNode
+	var titles []titleTarget
+	walkNodes(doc, func(node nodes.Node) {
+		switch n := node.(type) {
+		case *nodes.TargetNode:
+			if n.IsAnonymous() {
+				anonymous = append(anonymous, n)
+			} else {
+				table.add(n)
+			}
+		case *nodes.LinkNode:
+			switch {
+			case n.IsAnonymous() && n.URL() == "":
+				anonymousLinks = append(anonymousLinks, n)
+			case n.URL() != "" && n.RefName() != "":
+				// A reference with an embedded URI also defines a target
+				target := nodes.NewTargetNode(n.RefName())
+				target.SetURI(n.URL())
+				target.SetPosition(n.Start(), n.End())
+				table.add(target)
+			case n.URL() == "" && n.RefName() != "":
+				links = append(links, n)
+			}
+		case *nodes.SectionNode:
+			if heading := n.Heading(); heading != nil {
+				titles = append(titles, titleTarget{n, nodes.TextContent(heading)})
+			}
+		case *nodes.TitleNode, *nodes.SubtitleNode:
+			titles = append(titles, titleTarget{n, nodes.TextContent(n)})
+		}
+	})
+	// Section titles are implicit targets, which explicit ones override.
+	// A title used twice cannot be referred to by name.
+	implicit := make(map[string]bool)
+	for _, title := range titles {
+		name := normalizeName(title.text)
+		if _, ok := table.named[name]; name == "" || ok && !implicit[name] {
+			continue
+		} else if ok {
+			p.report(LevelInfo, title.node.Start(), "Duplicate implicit target name: %q.", name)
+			table.duplicates[name] = true
+			continue
+		}
+		target := nodes.NewTargetNode(name)
+		target.SetID(title.node.ID())
+		table.named[name] = target
+		implicit[name] = true
+	}
+
+	for _, link := range links {
+		target, ok := table.named[link.RefName()]
+		switch {
+		case !ok:
+			p.report(LevelError, link.Start(), "Unknown target name: %q.", link.RefName())
+		case table.duplicates[link.RefName()]:
+			p.report(LevelError, link.Start(), "Duplicate target name, cannot be used as a unique reference: %q.", link.RefName())
+		default:
+			table.resolve(link, target)
+		}
+	}
+	if len(anonymousLinks) != len(anonymous) {
+		var pos nodes.Position
+		if len(anonymousLinks) > 0 {
+			pos = anonymousLinks[0].Start()
+		} else {
+			pos = anonymous[0].Start()
+		}
+		p.report(LevelError, pos, "Anonymous hyperlink mismatch: %d references but %d targets.", len(anonymousLinks), len(anonymous))
+		return
+	}
+	for i, link := range anonymousLinks {
+		table.resolve(link, anonymous[i])
+	}
+}
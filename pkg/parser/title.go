@@ -1 +1,65 @@
 package parser
+
+import "github.com/go-i2p/go-rst/pkg/nodes"
+
+// SetDocTitle enables or disables promotion of a lone top-level section
+// title to the document title, and of a lone subsection title after it to
+// the subtitle, as the docutils doctitle transform does. It is enabled by
+// default.
+func (p *Parser) SetDocTitle(enabled bool) {
+	p.docTitle = enabled
+}
+
+// promoteTitle turns the document's only top-level section into the document
+// body, its heading becoming a TitleNode. The section must be the only
+// element of the document apart from comments and metadata before it. It
+// returns the new body and the index of the title, or -1 if there is none.
+func (p *Parser) promoteTitle(body []nodes.Node) ([]nodes.Node, int) {
+	i, section := loneSection(body, 0)
+	if section == nil {
+		return body, -1
+	}
+	heading := section.Heading()
+	title := nodes.NewTitleNode(heading.Content(), heading.Level())
+	return unwrapSection(body, i, section, title), i
+}
+
+// loneSection returns the element of body following index start, ignoring
+// comments and metadata, if it is a section and the last element of body.
+func loneSection(body []nodes.Node, start int) (int, *nodes.SectionNode) {
+	i := start
+	for i < len(body) && isPreBibliographic(body[i]) {
+		i++
+	}
+	if i != len(body)-1 {
+		return -1, nil
+	}
+	section, ok := body[i].(*nodes.SectionNode)
+	if !ok || section.Heading() == nil {
+		return -1, nil
+	}
+	return i, section
+}
+
+// isPreBibliographic reports whether node may precede the document title.
+func isPreBibliographic(node nodes.Node) bool {
+	switch node.(type) {
+	case *nodes.CommentNode, *nodes.MetaNode:
+		return true
+	}
+	return false
+}
+
+// unwrapSection replaces the section at index i of body with titular, which
+// takes over the inline content and source span of the section heading,
+// followed by the body of the section.
+func unwrapSection(body []nodes.Node, i int, section *nodes.SectionNode, titular nodes.Node) []nodes.Node {
+	heading := section.Heading()
+	for _, child := range heading.Children() {
+		titular.AddChild(child)
+	}
+	titular.SetPosition(heading.Start(), heading.End())
+
+	result := append(body[:i:i], titular)
+	return append(result, section.Body()...)
+}
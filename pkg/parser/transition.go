@@ -9,7 +9,7 @@ import (
 // IsTransition checks if a line is a transition
 func (p *Patterns) IsTransition(line string) bool {
 	// A transition is a line with 4+ repeated punctuation characters
-	return len(strings.TrimSpace(line)) >= 4 && p.transition.MatchString(strings.TrimSpace(line))
+	return len(strings.TrimSpace(line)) >= 4 && p.adornment.MatchString(strings.TrimSpace(line))
 }
 
 // TransitionChar extracts the character used in the transition
@@ -22,14 +22,42 @@ func (p *Patterns) TransitionChar(line string) rune {
 }
 
 // processTransition handles the parsing of transition sections
-// A transition is a horizontal line separator typically used between sections
-func (p *Parser) processTransition(content string) *nodes.TransitionNode {
-	// Extract the character used in the transition
-	var transChar rune = '-' // Default to hyphen
-	if len(content) > 0 {
-		transChar = rune(content[0])
+// A transition is a horizontal line separator typically used between sections.
+// It returns nil, consuming nothing, unless the current line is a transition
+// standing on its own.
+func (p *Parser) processTransition(r *lineReader) *nodes.TransitionNode {
+	line := r.peek()
+	if !p.patterns.IsTransition(line.text) {
+		return nil
 	}
+	if next, ok := r.peekAt(1); ok && !next.isBlank() {
+		return nil
+	}
+	r.next()
 
 	// Create a new transition node with the character
-	return nodes.NewTransitionNode(transChar)
+	return nodes.NewTransitionNode(p.patterns.TransitionChar(line.text))
+}
+
+// checkTransitions reports transitions at the start of the document or a
+// section, at the end of the document, and transitions that are not separated
+// by another element. The body is at the end of the document if atEnd is set.
+func (p *Parser) checkTransitions(body []nodes.Node, atEnd bool) {
+	for i, node := range body {
+		if section, ok := node.(*nodes.SectionNode); ok {
+			p.checkTransitions(section.Body(), atEnd && i == len(body)-1)
+			continue
+		}
+		if node.Type() != nodes.NodeTransition {
+			continue
+		}
+		switch {
+		case i == 0:
+			p.report(LevelError, node.Start(), "Document or section may not begin with a transition.")
+		case body[i-1].Type() == nodes.NodeTransition:
+			p.report(LevelError, node.Start(), "At least one body element must separate transitions; adjacent transitions are not allowed.")
+		case i == len(body)-1 && atEnd:
+			p.report(LevelError, node.Start(), "Document may not end with a transition.")
+		}
+	}
 }
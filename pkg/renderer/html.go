@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"fmt"
 	"html"
+	"slices"
 	"strings"
 
 	"github.com/go-i2p/go-rst/pkg/nodes"
@@ -40,13 +41,35 @@ func (r *HTMLRenderer) Render(nodes []nodes.Node) string {
 
 func (r *HTMLRenderer) renderMeta(nodelist []nodes.Node) {
 	r.buffer.WriteString("<meta charset=\"UTF-8\">\n")
+	if title, _ := documentTitle(nodelist); title != "" {
+		r.buffer.WriteString(fmt.Sprintf("<title>%s</title>\n", html.EscapeString(title)))
+	}
+
+	if docInfo := documentInfo(nodelist); docInfo != nil {
+		for _, author := range docInfo.Authors() {
+			r.buffer.WriteString(fmt.Sprintf("<meta name=\"author\" content=\"%s\">\n", html.EscapeString(author)))
+		}
+		if date := docInfo.Date(); date != "" {
+			r.buffer.WriteString(fmt.Sprintf("<meta name=\"dcterms.date\" content=\"%s\">\n", html.EscapeString(date)))
+		}
+		if copyright := docInfo.Copyright(); copyright != "" {
+			r.buffer.WriteString(fmt.Sprintf("<meta name=\"copyright\" content=\"%s\">\n", html.EscapeString(copyright)))
+		}
+	}
+	r.renderMetaNodes(nodelist)
+}
 
+// renderMetaNodes writes the meta directives found anywhere in nodelist,
+// including those inside sections.
+func (r *HTMLRenderer) renderMetaNodes(nodelist []nodes.Node) {
 	for _, node := range nodelist {
 		switch n := node.(type) {
 		case *nodes.MetaNode:
 			r.buffer.WriteString(fmt.Sprintf("<meta name=\"%s\" content=\"%s\">\n",
 				html.EscapeString(n.Key()),
 				html.EscapeString(n.Content())))
+		case *nodes.SectionNode:
+			r.renderMetaNodes(n.Children())
 		}
 	}
 }
@@ -54,34 +77,90 @@ func (r *HTMLRenderer) renderMeta(nodelist []nodes.Node) {
 func (r *HTMLRenderer) renderNode(node nodes.Node) {
 	switch n := node.(type) {
 	case *nodes.HeadingNode:
-		r.buffer.WriteString(fmt.Sprintf("<h%d>%s</h%d>\n",
-			n.Level(),
-			html.EscapeString(n.Content()),
-			n.Level()))
+		// HTML has no heading elements below h6
+		level := min(n.Level(), 6)
+		r.buffer.WriteString(fmt.Sprintf("<h%d>", level))
+		r.renderInline(n)
+		r.buffer.WriteString(fmt.Sprintf("</h%d>\n", level))
 
 	case *nodes.ParagraphNode:
-		r.buffer.WriteString(fmt.Sprintf("<p>%s</p>\n",
+		r.buffer.WriteString("<p>")
+		r.renderInline(n)
+		r.buffer.WriteString("</p>\n")
+
+	case *nodes.TextNode:
+		r.buffer.WriteString(html.EscapeString(n.Content()))
+
+	case *nodes.LiteralNode:
+		r.buffer.WriteString(fmt.Sprintf("<code>%s</code>",
 			html.EscapeString(n.Content())))
 
+	case *nodes.InterpretedTextNode:
+		if n.Role() == "" || n.Role() == "title-reference" {
+			r.buffer.WriteString(fmt.Sprintf("<cite>%s</cite>",
+				html.EscapeString(n.Content())))
+		} else {
+			r.buffer.WriteString(fmt.Sprintf("<span class=\"%s\">%s</span>",
+				html.EscapeString(n.Role()),
+				html.EscapeString(n.Content())))
+		}
+
+	case *nodes.SubstitutionReferenceNode:
+		if len(n.Children()) == 0 {
+			r.buffer.WriteString(html.EscapeString("|" + n.Name() + "|"))
+		} else {
+			r.renderChildren(n)
+		}
+
+	case *nodes.InlineNode:
+		r.renderInlineRole(n)
+
 	case *nodes.ListNode:
-		tag := "ul"
+		tag, attributes := "ul", ""
 		if n.IsOrdered() {
-			tag = "ol"
+			tag, attributes = "ol", orderedListAttributes(n)
 		}
-		r.buffer.WriteString(fmt.Sprintf("<%s>\n", tag))
+		r.buffer.WriteString(fmt.Sprintf("<%s%s>\n", tag, attributes))
 		for _, child := range n.Children() {
 			if item, ok := child.(*nodes.ListItemNode); ok {
-				r.buffer.WriteString(fmt.Sprintf("<li>%s</li>\n",
-					html.EscapeString(item.Content())))
+				r.renderListItem(item)
 			}
 		}
 		r.buffer.WriteString(fmt.Sprintf("</%s>\n", tag))
 
+	case *nodes.DefinitionListNode:
+		r.buffer.WriteString("<dl>\n")
+		for _, child := range n.Children() {
+			if item, ok := child.(*nodes.DefinitionListItemNode); ok {
+				r.renderDefinitionListItem(item)
+			}
+		}
+		r.buffer.WriteString("</dl>\n")
+
+	case *nodes.FieldListNode:
+		r.renderFields("field-list", n.Children())
+	case *nodes.DocInfoNode:
+		r.renderFields("docinfo", n.Children())
+	case *nodes.OptionListNode:
+		r.renderOptionList(n)
+
 	case *nodes.LinkNode:
-		r.buffer.WriteString(fmt.Sprintf("<a href=\"%s\" title=\"%s\">%s</a>",
-			html.EscapeString(n.URL()),
-			html.EscapeString(n.Title()),
-			html.EscapeString(n.Content())))
+		href := n.URL()
+		if href == "" && n.RefID() != "" {
+			href = "#" + n.RefID()
+		}
+		if href == "" {
+			// An unresolved reference, which the parser reported
+			r.buffer.WriteString("<span class=\"problematic\">")
+			r.renderInline(n)
+			r.buffer.WriteString("</span>")
+			break
+		}
+		r.buffer.WriteString(fmt.Sprintf("<a href=\"%s\" title=\"%s\">",
+			html.EscapeString(href),
+			html.EscapeString(n.Title())))
+		r.renderInline(n)
+		r.buffer.WriteString("</a>")
 
 	case *nodes.EmphasisNode:
 		r.buffer.WriteString(fmt.Sprintf("<em>%s</em>",
@@ -92,6 +171,11 @@ func (r *HTMLRenderer) renderNode(node nodes.Node) {
 			html.EscapeString(n.Content())))
 
 	case *nodes.CodeNode:
+		if n.Language() == "" {
+			r.buffer.WriteString(fmt.Sprintf("<pre class=\"literal-block\">%s</pre>\n",
+				html.EscapeString(n.Content())))
+			break
+		}
 		r.buffer.WriteString(fmt.Sprintf("<pre><code class=\"language-%s\">%s</code></pre>\n",
 			html.EscapeString(n.Language()),
 			html.EscapeString(n.Content())))
@@ -101,9 +185,29 @@ func (r *HTMLRenderer) renderNode(node nodes.Node) {
 
 	case *nodes.DirectiveNode:
 		r.renderDirective(n)
+	case *nodes.AdmonitionNode:
+		class := "admonition"
+		if n.Kind() != "admonition" {
+			class += " " + n.Kind()
+		}
+		r.buffer.WriteString(fmt.Sprintf("<div class=\"%s\">\n", html.EscapeString(class)))
+		r.buffer.WriteString(fmt.Sprintf("<p class=\"admonition-title\">%s</p>\n", html.EscapeString(n.Title())))
+		r.renderChildren(n)
+		r.buffer.WriteString("</div>\n")
+	case *nodes.ImageNode:
+		r.renderImage(n)
+	case *nodes.FootnoteReferenceNode:
+		r.renderFootnoteReference(n)
+	case *nodes.FootnoteNode:
+		r.renderFootnote(n)
+	case *nodes.RawNode:
+		if n.HasFormat("html") {
+			r.buffer.WriteString(n.Content() + "\n")
+		}
 	case *nodes.BlockQuoteNode:
 		r.buffer.WriteString("<blockquote>")
 		r.buffer.WriteString(html.EscapeString(n.Content()))
+		r.renderChildren(n)
 		if attr := n.Attribution(); attr != "" {
 			r.buffer.WriteString("<cite>")
 			r.buffer.WriteString(html.EscapeString(attr))
@@ -112,13 +216,19 @@ func (r *HTMLRenderer) renderNode(node nodes.Node) {
 		r.buffer.WriteString("</blockquote>\n")
 	case *nodes.DoctestNode:
 		r.buffer.WriteString("<div class=\"doctest\">")
-		r.buffer.WriteString("<pre class=\"doctest-command\">>> ")
-		r.buffer.WriteString(html.EscapeString(n.Command()))
-		r.buffer.WriteString("</pre>")
-		if n.Expected() != "" {
-			r.buffer.WriteString("<pre class=\"doctest-output\">")
-			r.buffer.WriteString(html.EscapeString(n.Expected()))
+		for _, example := range n.Examples() {
+			r.buffer.WriteString("<pre class=\"doctest-command\">&gt;&gt;&gt; ")
+			r.buffer.WriteString(html.EscapeString(example.Command))
+			for _, line := range example.Continuation {
+				r.buffer.WriteString("\n... ")
+				r.buffer.WriteString(html.EscapeString(line))
+			}
 			r.buffer.WriteString("</pre>")
+			if example.Expected != "" {
+				r.buffer.WriteString("<pre class=\"doctest-output\">")
+				r.buffer.WriteString(html.EscapeString(example.Expected))
+				r.buffer.WriteString("</pre>")
+			}
 		}
 		r.buffer.WriteString("</div>\n")
 	case *nodes.LineBlockNode:
@@ -134,63 +244,352 @@ func (r *HTMLRenderer) renderNode(node nodes.Node) {
 		r.buffer.WriteString(html.EscapeString(n.Content()))
 		r.buffer.WriteString(" -->\n")
 	case *nodes.TitleNode:
-		r.buffer.WriteString(fmt.Sprintf("<h1 class=\"title\">%s</h1>\n",
-			html.EscapeString(n.Content())))
+		r.buffer.WriteString(fmt.Sprintf("<h1 class=\"title\"%s>", idAttribute(n)))
+		r.renderInline(n)
+		r.buffer.WriteString("</h1>\n")
 	case *nodes.SubtitleNode:
-		r.buffer.WriteString(fmt.Sprintf("<h2 class=\"subtitle\">%s</h2>\n",
-			html.EscapeString(n.Content())))
+		r.buffer.WriteString(fmt.Sprintf("<h2 class=\"subtitle\"%s>", idAttribute(n)))
+		r.renderInline(n)
+		r.buffer.WriteString("</h2>\n")
+	case *nodes.SectionNode:
+		r.buffer.WriteString(fmt.Sprintf("<section%s>\n", idAttribute(n)))
+		r.renderChildren(n)
+		r.buffer.WriteString("</section>\n")
 	case *nodes.TransitionNode:
 		r.buffer.WriteString("<hr class=\"docutils\">\n")
+	case *nodes.TargetNode:
+		// Only internal targets mark a place in the document
+		if n.IsInternal() && n.ID() != "" {
+			r.buffer.WriteString(fmt.Sprintf("<span%s></span>\n", idAttribute(n)))
+		}
+	}
+}
+
+// renderFields renders fields as a description list of the given class.
+func (r *HTMLRenderer) renderFields(class string, fields []nodes.Node) {
+	r.buffer.WriteString(fmt.Sprintf("<dl class=\"%s\">\n", class))
+	for _, child := range fields {
+		field, ok := child.(*nodes.FieldNode)
+		if !ok {
+			continue
+		}
+		r.buffer.WriteString(fmt.Sprintf("<dt>%s</dt>\n<dd>\n", html.EscapeString(field.Name())))
+		r.renderChildren(field)
+		r.buffer.WriteString("</dd>\n")
+	}
+	r.buffer.WriteString("</dl>\n")
+}
+
+// renderOptionList renders an option list as a two-column table of option
+// groups and their descriptions.
+func (r *HTMLRenderer) renderOptionList(list *nodes.OptionListNode) {
+	r.buffer.WriteString("<table class=\"option-list\">\n<tbody>\n")
+	for _, child := range list.Children() {
+		item, ok := child.(*nodes.OptionListItemNode)
+		if !ok {
+			continue
+		}
+		r.buffer.WriteString("<tr>\n<td class=\"option-group\"><kbd>")
+		for i, option := range item.Options() {
+			if i > 0 {
+				r.buffer.WriteString(", ")
+			}
+			r.buffer.WriteString(fmt.Sprintf("<span class=\"option\">%s", html.EscapeString(option.Name)))
+			if option.Argument != "" {
+				r.buffer.WriteString(fmt.Sprintf("%s<var>%s</var>",
+					html.EscapeString(option.Delimiter), html.EscapeString(option.Argument)))
+			}
+			r.buffer.WriteString("</span>")
+		}
+		r.buffer.WriteString("</kbd></td>\n<td>\n")
+		for _, description := range item.Description() {
+			r.renderNode(description)
+		}
+		r.buffer.WriteString("</td>\n</tr>\n")
+	}
+	r.buffer.WriteString("</tbody>\n</table>\n")
+}
+
+// renderDefinitionListItem renders a term, its classifiers and its definition.
+func (r *HTMLRenderer) renderDefinitionListItem(item *nodes.DefinitionListItemNode) {
+	r.buffer.WriteString("<dt>")
+	r.renderInline(item.Term())
+	for _, classifier := range item.Classifiers() {
+		r.buffer.WriteString(fmt.Sprintf(" : <span class=\"classifier\">%s</span>", html.EscapeString(classifier)))
+	}
+	r.buffer.WriteString("</dt>\n<dd>\n")
+	for _, child := range item.Definition() {
+		r.renderNode(child)
+	}
+	r.buffer.WriteString("</dd>\n")
+}
+
+// orderedListAttributes returns the type and start attributes of an <ol>
+// element for the enumeration of list, omitting defaults.
+func orderedListAttributes(list *nodes.ListNode) string {
+	attributes := ""
+	switch list.EnumType() {
+	case nodes.EnumLowerAlpha:
+		attributes += ` type="a"`
+	case nodes.EnumUpperAlpha:
+		attributes += ` type="A"`
+	case nodes.EnumLowerRoman:
+		attributes += ` type="i"`
+	case nodes.EnumUpperRoman:
+		attributes += ` type="I"`
+	}
+	if start := list.StartValue(); start != 1 {
+		attributes += fmt.Sprintf(` start="%d"`, start)
+	}
+	return attributes
+}
+
+// renderListItem renders a list item. A leading paragraph is written without
+// its <p> wrapper so that simple lists stay compact.
+func (r *HTMLRenderer) renderListItem(item *nodes.ListItemNode) {
+	r.buffer.WriteString("<li>")
+	r.buffer.WriteString(html.EscapeString(item.Content()))
+	for i, child := range item.Children() {
+		if paragraph, ok := child.(*nodes.ParagraphNode); ok && i == 0 {
+			r.renderInline(paragraph)
+			continue
+		}
+		r.renderNode(child)
+	}
+	r.buffer.WriteString("</li>\n")
+}
+
+// renderInline renders the inline children of a text element, or its plain
+// content if it has none.
+func (r *HTMLRenderer) renderInline(node nodes.Node) {
+	if len(node.Children()) == 0 {
+		r.buffer.WriteString(html.EscapeString(node.Content()))
+		return
+	}
+	r.renderChildren(node)
+}
+
+// renderChildren renders the child nodes of a container node.
+func (r *HTMLRenderer) renderChildren(node nodes.Node) {
+	for _, child := range node.Children() {
+		r.renderNode(child)
 	}
 }
 
 func (r *HTMLRenderer) renderTable(table *nodes.TableNode) {
-	r.buffer.WriteString("<table>\n")
+	if align := table.Align(); align != "" {
+		r.buffer.WriteString(fmt.Sprintf("<table class=\"align-%s\">\n", html.EscapeString(align)))
+	} else {
+		r.buffer.WriteString("<table>\n")
+	}
+	if title := table.Title(); title != "" {
+		r.buffer.WriteString(fmt.Sprintf("<caption>%s</caption>\n", html.EscapeString(title)))
+	}
+	r.renderColumnWidths(table.ColumnWidths())
 
-	// Render headers
-	if len(table.Headers()) > 0 {
-		r.buffer.WriteString("<thead><tr>\n")
-		for _, header := range table.Headers() {
-			r.buffer.WriteString(fmt.Sprintf("<th>%s</th>",
-				html.EscapeString(header)))
+	// Cells in the stub columns of the body are row headers
+	stubs := make(map[*nodes.TableCellNode]bool)
+	grid := tableGrid(table)
+	for i, row := range grid {
+		for j, cell := range row[:min(table.StubColumns(), len(row))] {
+			if isCellOrigin(grid, i, j) {
+				stubs[cell] = true
+			}
 		}
-		r.buffer.WriteString("</tr></thead>\n")
+	}
+
+	// Render headers
+	if headers := table.HeaderRows(); len(headers) > 0 {
+		r.buffer.WriteString("<thead>\n")
+		r.renderTableRows(headers, "th", nil)
+		r.buffer.WriteString("</thead>\n")
 	}
 
 	// Render rows
 	r.buffer.WriteString("<tbody>\n")
-	for _, row := range table.Rows() {
+	r.renderTableRows(table.BodyRows(), "td", stubs)
+	r.buffer.WriteString("</tbody></table>\n")
+}
+
+// renderColumnWidths renders the relative widths of the columns of a table
+// as percentages, if they are known.
+func (r *HTMLRenderer) renderColumnWidths(widths []int) {
+	total := 0
+	for _, width := range widths {
+		total += width
+	}
+	if total == 0 {
+		return
+	}
+	r.buffer.WriteString("<colgroup>\n")
+	for _, width := range widths {
+		r.buffer.WriteString(fmt.Sprintf("<col style=\"width: %d%%\">\n", (200*width+total)/(2*total)))
+	}
+	r.buffer.WriteString("</colgroup>\n")
+}
+
+// renderTableRows renders rows with cells of the given tag, except that stub
+// cells are rendered as row headers. Cells spanning several rows or columns
+// only appear in the row they start in.
+func (r *HTMLRenderer) renderTableRows(rows []*nodes.TableRowNode, tag string, stubs map[*nodes.TableCellNode]bool) {
+	for _, row := range rows {
 		r.buffer.WriteString("<tr>\n")
-		for _, cell := range row {
-			r.buffer.WriteString(fmt.Sprintf("<td>%s</td>",
-				html.EscapeString(cell)))
+		for _, cell := range row.Cells() {
+			tag, attributes := tag, ""
+			if stubs[cell] {
+				tag, attributes = "th", ` class="stub"`
+			}
+			if cell.RowSpan() > 1 {
+				attributes += fmt.Sprintf(` rowspan="%d"`, cell.RowSpan())
+			}
+			if cell.ColSpan() > 1 {
+				attributes += fmt.Sprintf(` colspan="%d"`, cell.ColSpan())
+			}
+			r.buffer.WriteString(fmt.Sprintf("<%s%s>", tag, attributes))
+			if len(cell.Children()) == 0 {
+				r.buffer.WriteString(html.EscapeString(cell.Content()))
+			}
+			r.renderChildren(cell)
+			r.buffer.WriteString(fmt.Sprintf("</%s>", tag))
 		}
 		r.buffer.WriteString("</tr>\n")
 	}
-	r.buffer.WriteString("</tbody></table>\n")
 }
 
-func (r *HTMLRenderer) renderDirective(directive *nodes.DirectiveNode) {
-	switch directive.Name() {
-	case "image":
-		if len(directive.Arguments()) > 0 {
-			alt := ""
-			if len(directive.Arguments()) > 1 {
-				alt = strings.Join(directive.Arguments()[1:], " ")
-			}
-			r.buffer.WriteString(fmt.Sprintf("<img src=\"%s\" alt=\"%s\">\n",
-				html.EscapeString(directive.Arguments()[0]),
-				html.EscapeString(alt)))
+// inlineTags maps the kinds of inline text produced by roles to their HTML
+// elements.
+var inlineTags = map[nodes.InlineKind]string{
+	nodes.InlineSpan:         "span",
+	nodes.InlineSubscript:    "sub",
+	nodes.InlineSuperscript:  "sup",
+	nodes.InlineAbbreviation: "abbr",
+	nodes.InlineCode:         "code",
+}
+
+// renderInlineRole renders inline text produced by an interpreted text role.
+// Raw text is written as is when it is meant for HTML and dropped otherwise.
+func (r *HTMLRenderer) renderInlineRole(n *nodes.InlineNode) {
+	if n.Kind() == nodes.InlineRaw {
+		if n.HasFormat("html") {
+			r.buffer.WriteString(n.Content())
 		}
+		return
+	}
+	classes := n.Classes()
+	if n.Kind() == nodes.InlineCode {
+		classes = append([]string{"code"}, classes...)
+		if n.Language() != "" && !slices.Contains(classes, n.Language()) {
+			classes = append(classes, n.Language())
+		}
+	}
+	tag := inlineTags[n.Kind()]
+	r.buffer.WriteString("<" + tag)
+	if len(classes) > 0 {
+		r.buffer.WriteString(fmt.Sprintf(" class=\"%s\"", html.EscapeString(strings.Join(classes, " "))))
+	}
+	r.buffer.WriteString(">")
+	if len(n.Children()) > 0 {
+		r.renderChildren(n)
+	} else {
+		r.buffer.WriteString(html.EscapeString(n.Content()))
+	}
+	r.buffer.WriteString("</" + tag + ">")
+}
 
-	case "note":
-		r.buffer.WriteString(fmt.Sprintf("<div class=\"note\">%s</div>\n",
-			html.EscapeString(directive.RawContent())))
+// renderDirective renders a directive without a node of its own as a
+// division, classed by the directive name, holding its parsed content.
+func (r *HTMLRenderer) renderDirective(directive *nodes.DirectiveNode) {
+	if len(directive.Children()) == 0 {
+		return
+	}
+	r.buffer.WriteString(fmt.Sprintf("<div class=\"%s\">\n", html.EscapeString(strings.ToLower(directive.Name()))))
+	r.renderChildren(directive)
+	r.buffer.WriteString("</div>\n")
+}
 
-	case "warning":
-		r.buffer.WriteString(fmt.Sprintf("<div class=\"warning\">%s</div>\n",
-			html.EscapeString(directive.RawContent())))
+// renderImage renders an image, linked to its target if it has one.
+func (r *HTMLRenderer) renderImage(image *nodes.ImageNode) {
+	if image.Target() != "" {
+		r.buffer.WriteString(fmt.Sprintf("<a href=\"%s\">", html.EscapeString(image.Target())))
+	}
+	r.buffer.WriteString(fmt.Sprintf("<img src=\"%s\" alt=\"%s\"",
+		html.EscapeString(image.URI()),
+		html.EscapeString(image.Alt())))
+	var style []string
+	if image.Width() != "" {
+		style = append(style, "width: "+image.Width())
+	}
+	if image.Height() != "" {
+		style = append(style, "height: "+image.Height())
+	}
+	if len(style) > 0 {
+		r.buffer.WriteString(fmt.Sprintf(" style=\"%s;\"", html.EscapeString(strings.Join(style, "; "))))
+	}
+	if image.Align() != "" {
+		r.buffer.WriteString(fmt.Sprintf(" class=\"align-%s\"", html.EscapeString(image.Align())))
+	}
+	r.buffer.WriteString(">")
+	if image.Target() != "" {
+		r.buffer.WriteString("</a>")
+	}
+	r.buffer.WriteString("\n")
+}
+
+// footnoteClass returns the class of a footnote or citation element.
+func footnoteClass(citation bool) string {
+	if citation {
+		return "citation"
+	}
+	return "footnote"
+}
+
+// idAttribute returns the id attribute of the element for node, or "" if
+// the node has no ID.
+func idAttribute(node nodes.Node) string {
+	if node.ID() == "" {
+		return ""
+	}
+	return fmt.Sprintf(" id=\"%s\"", html.EscapeString(node.ID()))
+}
+
+// renderFootnoteReference renders a reference as a bracketed label linking
+// to the footnote. An unresolved reference is written as plain text.
+func (r *HTMLRenderer) renderFootnoteReference(ref *nodes.FootnoteReferenceNode) {
+	label := html.EscapeString("[" + ref.Label() + "]")
+	if ref.RefID() == "" {
+		r.buffer.WriteString(label)
+		return
+	}
+	r.buffer.WriteString(fmt.Sprintf("<a class=\"%s-reference\" href=\"#%s\" id=\"%s\">%s</a>",
+		footnoteClass(ref.IsCitation()),
+		html.EscapeString(ref.RefID()),
+		html.EscapeString(ref.ID()),
+		label))
+}
+
+// renderFootnote renders a footnote or citation with links back to its
+// references: on the label if there is one reference, or after it if there
+// are several.
+func (r *HTMLRenderer) renderFootnote(footnote *nodes.FootnoteNode) {
+	r.buffer.WriteString(fmt.Sprintf("<aside class=\"%s\"%s>\n<span class=\"label\">[",
+		footnoteClass(footnote.IsCitation()), idAttribute(footnote)))
+	label := html.EscapeString(footnote.Label())
+	backrefs := footnote.Backrefs()
+	if len(backrefs) == 1 {
+		r.buffer.WriteString(fmt.Sprintf("<a class=\"backref\" href=\"#%s\">%s</a>", html.EscapeString(backrefs[0]), label))
+	} else {
+		r.buffer.WriteString(label)
+	}
+	r.buffer.WriteString("]</span>\n")
+	if len(backrefs) > 1 {
+		links := make([]string, len(backrefs))
+		for i, id := range backrefs {
+			links[i] = fmt.Sprintf("<a class=\"backref\" href=\"#%s\">%d</a>", html.EscapeString(id), i+1)
+		}
+		r.buffer.WriteString(fmt.Sprintf("<span class=\"backrefs\">(%s)</span>\n", strings.Join(links, ", ")))
 	}
+	r.renderChildren(footnote)
+	r.buffer.WriteString("</aside>\n")
 }
 
 // RenderPretty renders the given nodes as pretty-formatted HTML.
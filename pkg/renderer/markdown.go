@@ -5,6 +5,7 @@ package renderer
 import (
 	"bytes"
 	"fmt"
+	"html"
 	"strings"
 
 	"github.com/go-i2p/go-rst/pkg/nodes"
@@ -24,8 +25,10 @@ func NewMarkdownRenderer() *MarkdownRenderer {
 
 // ... (previous imports and struct definition remain the same)
 
-// Render renders a slice of nodes to Markdown
+// Render renders a slice of nodes to Markdown.
+// The document title and subtitle, if any, are written as YAML front matter.
 func (r *MarkdownRenderer) Render(nodes []nodes.Node) error {
+	r.renderFrontMatter(nodes)
 	for _, node := range nodes {
 		if err := r.RenderNode(node); err != nil {
 			return err
@@ -53,48 +56,277 @@ func (r *MarkdownRenderer) RenderNode(node nodes.Node) error {
 		return r.RenderLink(n)
 	case *nodes.CodeNode:
 		return r.RenderCode(n)
+	case *nodes.DoctestNode:
+		return r.RenderDoctest(n)
 	case *nodes.TableNode:
 		return r.RenderTable(n)
 	case *nodes.DirectiveNode:
 		return r.RenderDirective(n)
+	case *nodes.RawNode:
+		// Markdown allows HTML in place of block elements
+		if n.HasFormat("html") || n.HasFormat("markdown") {
+			r.output.WriteString("\n" + n.Content() + "\n")
+		}
+		return nil
+	case *nodes.AdmonitionNode:
+		return r.RenderAdmonition(n)
+	case *nodes.ImageNode:
+		return r.RenderImage(n)
+	case *nodes.FootnoteNode:
+		return r.RenderFootnote(n)
 	case *nodes.MetaNode:
 		return r.RenderMeta(n)
+	case *nodes.BlockQuoteNode:
+		return r.RenderBlockQuote(n)
+	case *nodes.DefinitionListNode:
+		return r.RenderDefinitionList(n)
+	case *nodes.OptionListNode:
+		return r.RenderOptionList(n)
+	case *nodes.FieldListNode:
+		return r.renderFields(n.Children())
+	case *nodes.DocInfoNode:
+		return r.RenderDocInfo(n)
+	case *nodes.TitleNode, *nodes.SubtitleNode:
+		// Written as front matter by Render, leaving the anchor in place
+		r.renderAnchor(n)
+		return nil
+	case *nodes.SectionNode:
+		r.renderAnchor(n)
+		return r.RenderChildren(n)
+	case *nodes.TargetNode:
+		// Only internal targets mark a place in the document
+		if n.IsInternal() {
+			r.renderAnchor(n)
+		}
+		return nil
+	case *nodes.TextNode:
+		r.output.WriteString(n.Content())
+		return nil
+	case *nodes.LiteralNode:
+		r.output.WriteString("`" + n.Content() + "`")
+		return nil
+	case *nodes.InterpretedTextNode:
+		r.output.WriteString("*" + n.Content() + "*")
+		return nil
+	case *nodes.SubstitutionReferenceNode:
+		if len(n.Children()) == 0 {
+			r.output.WriteString("|" + n.Name() + "|")
+			return nil
+		}
+		for _, child := range n.Children() {
+			// An image substitution stays within its paragraph
+			if image, ok := child.(*nodes.ImageNode); ok {
+				r.output.WriteString(markdownImage(image))
+			} else if err := r.RenderNode(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *nodes.InlineNode:
+		return r.RenderInline(n)
+	case *nodes.FootnoteReferenceNode:
+		if n.RefID() == "" {
+			r.output.WriteString("[" + n.Label() + "]")
+			return nil
+		}
+		r.output.WriteString("[^" + n.Label() + "]")
+		return nil
 	default:
 		return r.RenderChildren(node)
 	}
 }
 
+// RenderInline renders inline text produced by an interpreted text role.
+// Markdown has no subscripts or superscripts, so they are written as HTML,
+// as is raw text meant for HTML or Markdown.
+func (r *MarkdownRenderer) RenderInline(node *nodes.InlineNode) error {
+	switch node.Kind() {
+	case nodes.InlineCode:
+		r.output.WriteString("`" + node.Content() + "`")
+	case nodes.InlineSubscript:
+		r.output.WriteString("<sub>" + node.Content() + "</sub>")
+	case nodes.InlineSuperscript:
+		r.output.WriteString("<sup>" + node.Content() + "</sup>")
+	case nodes.InlineRaw:
+		if node.HasFormat("html") || node.HasFormat("markdown") {
+			r.output.WriteString(node.Content())
+		}
+	default:
+		if len(node.Children()) > 0 {
+			return r.RenderChildren(node)
+		}
+		r.output.WriteString(node.Content())
+	}
+	return nil
+}
+
 // RenderHeading renders a heading node
 func (r *MarkdownRenderer) RenderHeading(node *nodes.HeadingNode) error {
 	r.output.WriteString("\n")
-	r.output.WriteString(strings.Repeat("#", node.Level()))
+	// Markdown has no heading levels below six
+	r.output.WriteString(strings.Repeat("#", min(node.Level(), 6)))
 	r.output.WriteString(" ")
-	r.output.WriteString(node.Content())
+	if err := r.renderInline(node); err != nil {
+		return err
+	}
 	r.output.WriteString("\n")
-	return r.RenderChildren(node)
+	return nil
 }
 
 // RenderParagraph renders a paragraph node
 func (r *MarkdownRenderer) RenderParagraph(node *nodes.ParagraphNode) error {
 	r.output.WriteString("\n")
-	r.output.WriteString(node.Content())
+	if err := r.renderInline(node); err != nil {
+		return err
+	}
 	r.output.WriteString("\n")
-	return r.RenderChildren(node)
+	return nil
 }
 
 // RenderList renders a list node
 func (r *MarkdownRenderer) RenderList(node *nodes.ListNode) error {
 	r.output.WriteString("\n")
-	return r.RenderChildren(node)
+	for i, child := range node.Children() {
+		item, ok := child.(*nodes.ListItemNode)
+		if !ok {
+			continue
+		}
+		// Markdown only numbers lists in arabic, with a "." or ")" suffix
+		marker := "- "
+		if node.IsOrdered() {
+			suffix := "."
+			if node.Suffix() == ")" && node.Prefix() == "" {
+				suffix = ")"
+			}
+			marker = fmt.Sprintf("%d%s ", node.StartValue()+i, suffix)
+		}
+		if err := r.renderListItem(marker, item); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // RenderListItem renders a list item node
 func (r *MarkdownRenderer) RenderListItem(node *nodes.ListItemNode) error {
 	// Default to unordered list items with "-"
-	r.output.WriteString("- ")
-	r.output.WriteString(node.Content())
+	return r.renderListItem("- ", node)
+}
+
+// renderListItem writes the marker followed by the item's first paragraph, and
+// nests the remaining body elements under it.
+func (r *MarkdownRenderer) renderListItem(marker string, node *nodes.ListItemNode) error {
+	return r.renderItemBody(marker, node.Content(), node.Children())
+}
+
+// renderItemBody writes the marker followed by content and the first
+// paragraph of children, and nests the remaining children under it.
+func (r *MarkdownRenderer) renderItemBody(marker, content string, children []nodes.Node) error {
+	indent := strings.Repeat(" ", len(marker))
+	if paragraph, ok := firstParagraph(children); ok {
+		text, err := r.inlineString(paragraph)
+		if err != nil {
+			return err
+		}
+		content += text
+		children = children[1:]
+	}
+
+	r.output.WriteString(marker)
+	r.output.WriteString(strings.Replace(content, "\n", "\n"+indent, -1))
 	r.output.WriteString("\n")
-	return r.RenderChildren(node)
+	if len(children) == 0 {
+		return nil
+	}
+	r.output.WriteString("\n")
+	return r.renderIndented(indent, children)
+}
+
+// RenderDefinitionList renders a definition list in the extended Markdown
+// syntax, with each term in bold followed by its definition on a line
+// starting with a colon.
+func (r *MarkdownRenderer) RenderDefinitionList(node *nodes.DefinitionListNode) error {
+	for _, child := range node.Children() {
+		item, ok := child.(*nodes.DefinitionListItemNode)
+		if !ok {
+			continue
+		}
+		term, err := r.inlineString(item.Term())
+		if err != nil {
+			return err
+		}
+		r.output.WriteString("\n**" + term + "**")
+		for _, classifier := range item.Classifiers() {
+			r.output.WriteString(" *(" + classifier + ")*")
+		}
+		r.output.WriteString("\n")
+		if err := r.renderItemBody(":   ", "", item.Definition()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenderOptionList renders an option list like a definition list, with each
+// option group as inline code followed by its description.
+func (r *MarkdownRenderer) RenderOptionList(node *nodes.OptionListNode) error {
+	for _, child := range node.Children() {
+		item, ok := child.(*nodes.OptionListItemNode)
+		if !ok {
+			continue
+		}
+		r.output.WriteString("\n`" + item.OptionGroup() + "`\n")
+		if err := r.renderItemBody(":   ", "", item.Description()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenderDocInfo renders the bibliographic fields that are not part of the
+// front matter, such as the abstract, as a field list.
+func (r *MarkdownRenderer) RenderDocInfo(node *nodes.DocInfoNode) error {
+	fields := make([]nodes.Node, 0)
+	for _, child := range node.Children() {
+		if field, ok := child.(*nodes.FieldNode); ok && !inFrontMatter(node, field) {
+			fields = append(fields, field)
+		}
+	}
+	return r.renderFields(fields)
+}
+
+// renderFields renders fields as a list of items starting with the field name
+// in bold.
+func (r *MarkdownRenderer) renderFields(fields []nodes.Node) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	r.output.WriteString("\n")
+	for _, child := range fields {
+		field, ok := child.(*nodes.FieldNode)
+		if !ok {
+			continue
+		}
+		if err := r.renderItemBody("- ", "**"+field.Name()+":** ", field.Children()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenderBlockQuote renders a block quote node
+func (r *MarkdownRenderer) RenderBlockQuote(node *nodes.BlockQuoteNode) error {
+	r.output.WriteString("\n")
+	if node.Content() != "" {
+		r.output.WriteString("> " + strings.Replace(node.Content(), "\n", "\n> ", -1) + "\n")
+	}
+	if err := r.renderIndented("> ", node.Children()); err != nil {
+		return err
+	}
+	if attribution := node.Attribution(); attribution != "" {
+		r.output.WriteString(">\n> — " + attribution + "\n")
+	}
+	return nil
 }
 
 // RenderEmphasis renders an emphasis node
@@ -115,14 +347,35 @@ func (r *MarkdownRenderer) RenderStrong(node *nodes.StrongNode) error {
 
 // RenderLink renders a link node
 func (r *MarkdownRenderer) RenderLink(node *nodes.LinkNode) error {
+	text, err := r.inlineString(node)
+	if err != nil {
+		return err
+	}
+	url := node.URL()
+	if url == "" && node.RefID() != "" {
+		url = "#" + node.RefID()
+	}
+	if url == "" {
+		// An unresolved reference is written as plain text
+		r.output.WriteString(text)
+		return nil
+	}
 	if title := node.Title(); title != "" {
-		r.output.WriteString(fmt.Sprintf("[%s](%s \"%s\")", node.Content(), node.URL(), title))
+		r.output.WriteString(fmt.Sprintf("[%s](%s \"%s\")", text, url, title))
 	} else {
-		r.output.WriteString(fmt.Sprintf("[%s](%s)", node.Content(), node.URL()))
+		r.output.WriteString(fmt.Sprintf("[%s](%s)", text, url))
 	}
 	return nil
 }
 
+// renderAnchor writes an HTML anchor for the ID of node, which internal
+// links point to. Nothing is written for a node without an ID.
+func (r *MarkdownRenderer) renderAnchor(node nodes.Node) {
+	if node.ID() != "" {
+		r.output.WriteString(fmt.Sprintf("\n<a id=\"%s\"></a>\n", html.EscapeString(node.ID())))
+	}
+}
+
 // RenderCode renders a code node
 func (r *MarkdownRenderer) RenderCode(node *nodes.CodeNode) error {
 	r.output.WriteString("\n```")
@@ -135,53 +388,137 @@ func (r *MarkdownRenderer) RenderCode(node *nodes.CodeNode) error {
 	return nil
 }
 
-// RenderTable renders a table node
-func (r *MarkdownRenderer) RenderTable(node *nodes.TableNode) error {
-	headers := node.Headers()
-	rows := node.Rows()
+// RenderDoctest renders a doctest block as a fenced Python console session
+func (r *MarkdownRenderer) RenderDoctest(node *nodes.DoctestNode) error {
+	r.output.WriteString("\n```pycon\n")
+	r.output.WriteString(node.Text())
+	r.output.WriteString("\n```\n")
+	return nil
+}
 
-	// Headers
-	r.output.WriteString("\n|")
-	for _, header := range headers {
-		r.output.WriteString(fmt.Sprintf(" %s |", header))
+// RenderTable renders a table node as a pipe table. Markdown cells cannot
+// span rows or columns, so a spanning cell appears in its first position
+// and the positions it covers are left empty. Pipe tables have exactly one
+// header row: a table without header rows gets an empty one, and further
+// header rows become body rows. Cells in stub columns are set in bold.
+func (r *MarkdownRenderer) RenderTable(node *nodes.TableNode) error {
+	grid := tableGrid(node)
+	columns := node.Columns()
+	if len(node.HeaderRows()) == 0 {
+		grid = append([][]*nodes.TableCellNode{make([]*nodes.TableCellNode, columns)}, grid...)
 	}
-	r.output.WriteString("\n|")
 
-	// Separator
-	for range headers {
-		r.output.WriteString(" --- |")
-	}
 	r.output.WriteString("\n")
-
-	// Rows
-	for _, row := range rows {
+	if title := node.Title(); title != "" {
+		r.output.WriteString("**" + title + "**\n\n")
+	}
+	for i, row := range grid {
+		if i == 1 {
+			r.output.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+		}
 		r.output.WriteString("|")
-		for _, cell := range row {
-			r.output.WriteString(fmt.Sprintf(" %s |", cell))
+		for j, cell := range row {
+			text := ""
+			if isCellOrigin(grid, i, j) {
+				var err error
+				if text, err = r.cellString(cell); err != nil {
+					return err
+				}
+				// Stub cells of the body are row headers
+				if j < node.StubColumns() && i >= len(node.HeaderRows()) && text != "" {
+					text = "**" + text + "**"
+				}
+			}
+			r.output.WriteString(fmt.Sprintf(" %s |", text))
 		}
 		r.output.WriteString("\n")
 	}
+	// A table of a single row still needs the separator
+	if len(grid) == 1 {
+		r.output.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+	}
 	r.output.WriteString("\n")
 	return nil
 }
 
-// RenderDirective renders a directive node
+// cellString renders the body of a table cell on a single line. Paragraphs
+// are joined into one line each; the lines of other body elements, such as
+// list items, and the paragraphs are separated by line breaks.
+func (r *MarkdownRenderer) cellString(cell *nodes.TableCellNode) (string, error) {
+	if len(cell.Children()) == 0 {
+		return strings.ReplaceAll(cell.Content(), "|", "\\|"), nil
+	}
+	parts := make([]string, 0, len(cell.Children()))
+	for _, child := range cell.Children() {
+		if paragraph, ok := child.(*nodes.ParagraphNode); ok {
+			text, err := r.inlineString(paragraph)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, strings.Join(strings.Fields(text), " "))
+			continue
+		}
+		nested := NewMarkdownRenderer()
+		if err := nested.Render([]nodes.Node{child}); err != nil {
+			return "", err
+		}
+		for _, line := range strings.Split(nested.String(), "\n") {
+			if strings.TrimSpace(line) != "" {
+				parts = append(parts, line)
+			}
+		}
+	}
+	return strings.ReplaceAll(strings.Join(parts, "<br>"), "|", "\\|"), nil
+}
+
+// RenderDirective renders a directive without a node of its own: its parsed
+// content if it has any, otherwise an HTML comment holding its raw content
 func (r *MarkdownRenderer) RenderDirective(node *nodes.DirectiveNode) error {
-	switch node.Name() {
-	case "image":
-		if len(node.Arguments()) > 0 {
-			r.output.WriteString(fmt.Sprintf("\n![%s](%s)\n", node.Content(), node.Arguments()[0]))
-		}
-	case "note":
-		r.output.WriteString("\n> **Note**\n")
-		r.output.WriteString("> " + strings.Replace(node.RawContent(), "\n", "\n> ", -1) + "\n")
-	case "warning":
-		r.output.WriteString("\n> **Warning**\n")
-		r.output.WriteString("> " + strings.Replace(node.RawContent(), "\n", "\n> ", -1) + "\n")
-	default:
-		r.output.WriteString(fmt.Sprintf("\n<!-- %s: %s -->\n", node.Name(), node.RawContent()))
+	if len(node.Children()) > 0 {
+		return r.RenderChildren(node)
 	}
-	return r.RenderChildren(node)
+	r.output.WriteString(fmt.Sprintf("\n<!-- %s: %s -->\n", node.Name(), node.RawContent()))
+	return nil
+}
+
+// RenderAdmonition renders an admonition as a block quote headed by its title
+func (r *MarkdownRenderer) RenderAdmonition(node *nodes.AdmonitionNode) error {
+	r.output.WriteString(fmt.Sprintf("\n> **%s**\n", node.Title()))
+	r.output.WriteString(">\n")
+	return r.renderIndented("> ", node.Children())
+}
+
+// RenderFootnote renders a footnote or citation as a footnote definition
+// ("[^1]: Text"), with the lines after the first indented beneath it
+func (r *MarkdownRenderer) RenderFootnote(node *nodes.FootnoteNode) error {
+	nested := NewMarkdownRenderer()
+	if err := nested.Render(node.Children()); err != nil {
+		return err
+	}
+	lines := strings.Split(strings.Trim(nested.String(), "\n"), "\n")
+	r.output.WriteString(fmt.Sprintf("\n[^%s]: %s\n", node.Label(), lines[0]))
+	for _, line := range lines[1:] {
+		if line != "" {
+			line = "    " + line
+		}
+		r.output.WriteString(line + "\n")
+	}
+	return nil
+}
+
+// RenderImage renders an image, linked to its target if it has one
+func (r *MarkdownRenderer) RenderImage(node *nodes.ImageNode) error {
+	r.output.WriteString("\n" + markdownImage(node) + "\n")
+	return nil
+}
+
+// markdownImage returns the Markdown for an image, linked to its target
+func markdownImage(node *nodes.ImageNode) string {
+	image := fmt.Sprintf("![%s](%s)", node.Alt(), node.URI())
+	if node.Target() != "" {
+		image = fmt.Sprintf("[%s](%s)", image, node.Target())
+	}
+	return image
 }
 
 // RenderMeta renders a meta node
@@ -208,6 +545,166 @@ func (r *MarkdownRenderer) RenderChildren(node nodes.Node) error {
 	return nil
 }
 
+// renderInline writes the inline children of a text element, or its plain
+// content if it has none.
+func (r *MarkdownRenderer) renderInline(node nodes.Node) error {
+	if len(node.Children()) == 0 {
+		r.output.WriteString(node.Content())
+		return nil
+	}
+	return r.RenderChildren(node)
+}
+
+// inlineString renders the inline content of a text element to a string.
+func (r *MarkdownRenderer) inlineString(node nodes.Node) (string, error) {
+	nested := NewMarkdownRenderer()
+	if err := nested.renderInline(node); err != nil {
+		return "", err
+	}
+	return nested.String(), nil
+}
+
+// renderIndented renders nodes into a separate buffer and writes the result
+// with every line prefixed, which nests it under list items and block quotes.
+func (r *MarkdownRenderer) renderIndented(prefix string, children []nodes.Node) error {
+	nested := NewMarkdownRenderer()
+	if err := nested.Render(children); err != nil {
+		return err
+	}
+	text := strings.Trim(nested.String(), "\n")
+	if text == "" {
+		return nil
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if line == "" {
+			r.output.WriteString(strings.TrimRight(prefix, " ") + "\n")
+			continue
+		}
+		r.output.WriteString(prefix + line + "\n")
+	}
+	return nil
+}
+
+// renderFrontMatter writes the document title, subtitle and bibliographic
+// fields as YAML front matter. Nothing is written for a document with none
+// of them.
+func (r *MarkdownRenderer) renderFrontMatter(nodelist []nodes.Node) {
+	var matter strings.Builder
+	title, subtitle := documentTitle(nodelist)
+	writeValue := func(key, value string) {
+		if value != "" {
+			matter.WriteString(fmt.Sprintf("%s: %q\n", key, value))
+		}
+	}
+	writeValue("title", title)
+	writeValue("subtitle", subtitle)
+	if docInfo := documentInfo(nodelist); docInfo != nil {
+		if authors := docInfo.Authors(); len(authors) == 1 {
+			writeValue("author", authors[0])
+		} else if len(authors) > 1 {
+			matter.WriteString("authors:\n")
+			for _, author := range authors {
+				matter.WriteString(fmt.Sprintf("  - %q\n", author))
+			}
+		}
+		writeValue("date", docInfo.Date())
+		writeValue("version", docInfo.Version())
+		writeValue("status", docInfo.Status())
+		writeValue("copyright", docInfo.Copyright())
+	}
+	if matter.Len() == 0 {
+		return
+	}
+	r.output.WriteString("---\n")
+	r.output.WriteString(matter.String())
+	r.output.WriteString("---\n")
+}
+
+// documentTitle returns the plain text of the document title and subtitle
+// among the top-level nodes, or empty strings if there are none.
+func documentTitle(nodelist []nodes.Node) (title, subtitle string) {
+	for _, node := range nodelist {
+		switch node.(type) {
+		case *nodes.TitleNode:
+			title = nodes.TextContent(node)
+		case *nodes.SubtitleNode:
+			subtitle = nodes.TextContent(node)
+		}
+	}
+	return title, subtitle
+}
+
+// documentInfo returns the bibliographic fields among the top-level nodes, or
+// nil if there are none.
+func documentInfo(nodelist []nodes.Node) *nodes.DocInfoNode {
+	for _, node := range nodelist {
+		if docInfo, ok := node.(*nodes.DocInfoNode); ok {
+			return docInfo
+		}
+	}
+	return nil
+}
+
+// inFrontMatter reports whether a docinfo field was written as front matter.
+func inFrontMatter(docInfo *nodes.DocInfoNode, field *nodes.FieldNode) bool {
+	switch strings.ToLower(field.Name()) {
+	case "author", "authors":
+		return len(docInfo.Authors()) > 0
+	case "date":
+		return docInfo.Date() != ""
+	case "version":
+		return docInfo.Version() != ""
+	case "status":
+		return docInfo.Status() != ""
+	case "copyright":
+		return docInfo.Copyright() != ""
+	}
+	return false
+}
+
+// firstParagraph returns the first node if it is a paragraph.
+func firstParagraph(children []nodes.Node) (*nodes.ParagraphNode, bool) {
+	if len(children) == 0 {
+		return nil, false
+	}
+	paragraph, ok := children[0].(*nodes.ParagraphNode)
+	return paragraph, ok
+}
+
+// tableGrid lays out the cells of table on a grid of its rows and columns.
+// A cell spanning several rows or columns fills each position it covers.
+// Positions not covered by any cell are nil.
+func tableGrid(table *nodes.TableNode) [][]*nodes.TableCellNode {
+	rows := append(table.HeaderRows(), table.BodyRows()...)
+	columns := table.Columns()
+	grid := make([][]*nodes.TableCellNode, len(rows))
+	for i := range grid {
+		grid[i] = make([]*nodes.TableCellNode, columns)
+	}
+	for i, row := range rows {
+		col := 0
+		for _, cell := range row.Cells() {
+			for col < columns && grid[i][col] != nil {
+				col++
+			}
+			for y := i; y < min(i+cell.RowSpan(), len(rows)); y++ {
+				for x := col; x < min(col+cell.ColSpan(), columns); x++ {
+					grid[y][x] = cell
+				}
+			}
+			col += cell.ColSpan()
+		}
+	}
+	return grid
+}
+
+// isCellOrigin reports whether position i, j of grid is the top left
+// position of its cell.
+func isCellOrigin(grid [][]*nodes.TableCellNode, i, j int) bool {
+	cell := grid[i][j]
+	return cell != nil && (i == 0 || grid[i-1][j] != cell) && (j == 0 || grid[i][j-1] != cell)
+}
+
 // String returns the rendered markdown as a string
 func (r *MarkdownRenderer) String() string {
 	return r.output.String()
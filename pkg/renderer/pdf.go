@@ -18,6 +18,10 @@ type PDFRenderer struct {
 	fontSize   float64
 	lineHeight float64
 	indent     float64
+	// footnotes waits to be written at the end of the section
+	footnotes []*nodes.FootnoteNode
+	// links maps element IDs to the PDF links that target them
+	links map[string]int
 }
 
 // NewPDFRenderer creates a new PDF renderer
@@ -33,17 +37,29 @@ func NewPDFRenderer() *PDFRenderer {
 		fontSize:   12,
 		lineHeight: 6,
 		indent:     10,
+		links:      make(map[string]int),
 	}
 }
 
-// Render renders a slice of nodes to PDF
+// Render renders a slice of nodes to PDF. The document title and subtitle,
+// if any, are also set as the title and subject document properties.
 func (r *PDFRenderer) Render(nodes []nodes.Node) error {
+	title, subtitle := documentTitle(nodes)
+	if title != "" {
+		r.pdf.SetTitle(title, true)
+	}
+	if subtitle != "" {
+		r.pdf.SetSubject(subtitle, true)
+	}
+	if docInfo := documentInfo(nodes); docInfo != nil && docInfo.Author() != "" {
+		r.pdf.SetAuthor(docInfo.Author(), true)
+	}
 	for _, node := range nodes {
 		if err := r.renderNode(node); err != nil {
 			return err
 		}
 	}
-	return nil
+	return r.renderFootnotes()
 }
 
 // renderNode handles individual node rendering
@@ -57,12 +73,47 @@ func (r *PDFRenderer) renderNode(node nodes.Node) error {
 		return r.renderList(n)
 	case *nodes.CodeNode:
 		return r.renderCode(n)
+	case *nodes.DoctestNode:
+		return r.renderDoctest(n)
 	case *nodes.TableNode:
 		return r.renderTable(n)
 	case *nodes.DirectiveNode:
-		return r.renderDirective(n)
+		return r.renderChildren(n)
+	case *nodes.AdmonitionNode:
+		return r.renderAdmonition(n)
+	case *nodes.ImageNode:
+		return r.renderImage(n)
+	case *nodes.FootnoteNode:
+		r.footnotes = append(r.footnotes, n)
+		return nil
+	case *nodes.SectionNode:
+		r.anchor(n)
+		if err := r.renderChildren(n); err != nil {
+			return err
+		}
+		return r.renderFootnotes()
 	case *nodes.StrongNode:
 		return r.renderStrong(n)
+	case *nodes.BlockQuoteNode:
+		return r.renderBlockQuote(n)
+	case *nodes.DefinitionListNode:
+		return r.renderDefinitionList(n)
+	case *nodes.FieldListNode, *nodes.DocInfoNode:
+		return r.renderFields(n.Children())
+	case *nodes.OptionListNode:
+		return r.renderOptionList(n)
+	case *nodes.RawNode:
+		// Raw content is meant for other formats
+		return nil
+	case *nodes.TargetNode:
+		if n.IsInternal() {
+			r.anchor(n)
+		}
+		return nil
+	case *nodes.TitleNode:
+		return r.renderTitle(n, r.fontSize+10)
+	case *nodes.SubtitleNode:
+		return r.renderTitle(n, r.fontSize+4)
 	//case *nodes.EmphasisNode:
 	//return r.renderEmphasis(n)
 	default:
@@ -75,56 +126,290 @@ func (r *PDFRenderer) SaveToFile(filename string) error {
 	return r.pdf.OutputFileAndClose(filename)
 }
 
+// renderTitle writes the document title or subtitle centered on the page.
+func (r *PDFRenderer) renderTitle(node nodes.Node, fontSize float64) error {
+	r.anchor(node)
+	r.pdf.SetFont("Arial", "B", fontSize)
+	r.pdf.CellFormat(0, r.lineHeight*2, nodes.TextContent(node), "", 1, "C", false, 0, "")
+	r.pdf.Ln(r.lineHeight)
+	r.pdf.SetFont("Arial", "", r.fontSize)
+	return nil
+}
+
 func (r *PDFRenderer) renderHeading(node *nodes.HeadingNode) error {
-	// Calculate font size based on heading level
-	fontSize := r.fontSize + (6 - float64(node.Level())*2)
+	// Calculate font size based on heading level, treating levels below six
+	// like level six
+	fontSize := r.fontSize + (6 - float64(min(node.Level(), 6))*2)
 	r.pdf.SetFont("Arial", "B", fontSize)
 
 	// Add some spacing before heading
 	r.pdf.Ln(r.lineHeight)
 
-	r.pdf.Cell(0, r.lineHeight, node.Content())
+	r.pdf.Cell(0, r.lineHeight, nodes.TextContent(node))
 	r.pdf.Ln(r.lineHeight * 1.5)
 
 	// Reset font
 	r.pdf.SetFont("Arial", "", r.fontSize)
-	return r.renderChildren(node)
+	return nil
 }
 
 func (r *PDFRenderer) renderParagraph(node *nodes.ParagraphNode) error {
-	r.pdf.MultiCell(0, r.lineHeight, node.Content(), "", "", false)
+	r.writeInline(node)
 	r.pdf.Ln(r.lineHeight)
-	return r.renderChildren(node)
+	r.pdf.Ln(r.lineHeight)
+	return nil
+}
+
+// writeInline flows the inline children of a text element, switching fonts
+// for emphasis, strong text and literals.
+func (r *PDFRenderer) writeInline(node nodes.Node) {
+	if len(node.Children()) == 0 {
+		r.pdf.Write(r.lineHeight, node.Content())
+		return
+	}
+	for _, child := range node.Children() {
+		switch n := child.(type) {
+		case *nodes.EmphasisNode, *nodes.InterpretedTextNode:
+			r.pdf.SetFont("Arial", "I", r.fontSize)
+			r.pdf.Write(r.lineHeight, n.Content())
+			r.pdf.SetFont("Arial", "", r.fontSize)
+		case *nodes.StrongNode:
+			r.pdf.SetFont("Arial", "B", r.fontSize)
+			r.pdf.Write(r.lineHeight, n.Content())
+			r.pdf.SetFont("Arial", "", r.fontSize)
+		case *nodes.LiteralNode:
+			r.pdf.SetFont("Courier", "", r.fontSize)
+			r.pdf.Write(r.lineHeight, n.Content())
+			r.pdf.SetFont("Arial", "", r.fontSize)
+		case *nodes.LinkNode:
+			switch {
+			case n.URL() != "":
+				r.pdf.SetTextColor(0, 0, 238)
+				r.pdf.WriteLinkString(r.lineHeight, nodes.TextContent(n), n.URL())
+				r.pdf.SetTextColor(0, 0, 0)
+			case n.RefID() != "":
+				r.pdf.SetTextColor(0, 0, 238)
+				r.pdf.WriteLinkID(r.lineHeight, nodes.TextContent(n), r.link(n.RefID()))
+				r.pdf.SetTextColor(0, 0, 0)
+			default:
+				r.writeInline(n)
+			}
+		case *nodes.FootnoteReferenceNode:
+			label := "[" + n.Label() + "]"
+			if n.RefID() == "" {
+				r.pdf.Write(r.lineHeight, label)
+				break
+			}
+			r.pdf.SetLink(r.link(n.ID()), r.pdf.GetY(), -1)
+			r.pdf.SetTextColor(0, 0, 238)
+			r.pdf.WriteLinkID(r.lineHeight, label, r.link(n.RefID()))
+			r.pdf.SetTextColor(0, 0, 0)
+		case *nodes.SubstitutionReferenceNode:
+			if len(n.Children()) == 0 {
+				r.pdf.Write(r.lineHeight, "|"+n.Name()+"|")
+			} else {
+				r.writeInline(n)
+			}
+		case *nodes.InlineNode:
+			switch n.Kind() {
+			case nodes.InlineRaw:
+				// Raw text is meant for other formats
+			case nodes.InlineCode:
+				r.pdf.SetFont("Courier", "", r.fontSize)
+				r.pdf.Write(r.lineHeight, n.Content())
+				r.pdf.SetFont("Arial", "", r.fontSize)
+			default:
+				r.writeInline(n)
+			}
+		default:
+			r.writeInline(n)
+		}
+	}
 }
 
 func (r *PDFRenderer) renderList(node *nodes.ListNode) error {
-	currentX := r.pdf.GetX()
-	currentY := r.pdf.GetY()
+	left, _, _, _ := r.pdf.GetMargins()
 
 	for i, child := range node.Children() {
+		listItem, ok := child.(*nodes.ListItemNode)
+		if !ok {
+			continue
+		}
+
+		r.pdf.SetX(left)
 		if node.IsOrdered() {
-			// Ordered list: use numbers
-			r.pdf.SetXY(currentX, currentY)
-			r.pdf.Cell(10, r.lineHeight, fmt.Sprintf("%d.", i+1))
-			r.pdf.SetX(currentX + r.indent)
+			// Ordered list: use the list's own enumeration, such as "(b)"
+			r.pdf.Cell(r.indent, r.lineHeight, node.Marker(i))
 		} else {
 			// Unordered list: use bullets
-			r.pdf.SetXY(currentX, currentY)
-			r.pdf.Cell(10, r.lineHeight, "•")
-			r.pdf.SetX(currentX + r.indent)
+			r.pdf.Cell(r.indent, r.lineHeight, "•")
 		}
 
-		if listItem, ok := child.(*nodes.ListItemNode); ok {
-			r.pdf.MultiCell(0, r.lineHeight, listItem.Content(), "", "", false)
+		// Indent the item body so that nested blocks line up with its text
+		r.pdf.SetLeftMargin(left + r.indent)
+		r.pdf.SetX(left + r.indent)
+		if err := r.renderListItem(listItem); err != nil {
+			return err
 		}
+		r.pdf.SetLeftMargin(left)
+	}
+
+	r.pdf.SetX(left)
+	r.pdf.Ln(r.lineHeight)
+	return nil
+}
 
-		currentY = r.pdf.GetY() + r.lineHeight
+// renderListItem writes the item's first paragraph next to its marker and
+// renders the remaining body elements below it.
+func (r *PDFRenderer) renderListItem(node *nodes.ListItemNode) error {
+	children := node.Children()
+	if node.Content() != "" {
+		r.pdf.Write(r.lineHeight, node.Content())
+	}
+	if paragraph, ok := firstParagraph(children); ok {
+		r.writeInline(paragraph)
+		children = children[1:]
 	}
+	r.pdf.Ln(r.lineHeight)
+	for _, child := range children {
+		if err := r.renderNode(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderDefinitionList renders each term in bold, followed by its classifiers
+// in italics, with the definition indented below it.
+func (r *PDFRenderer) renderDefinitionList(node *nodes.DefinitionListNode) error {
+	left, _, _, _ := r.pdf.GetMargins()
+	for _, child := range node.Children() {
+		item, ok := child.(*nodes.DefinitionListItemNode)
+		if !ok {
+			continue
+		}
+		r.pdf.SetX(left)
+		r.pdf.SetFont("Arial", "B", r.fontSize)
+		r.pdf.Write(r.lineHeight, nodes.TextContent(item.Term()))
+		r.pdf.SetFont("Arial", "I", r.fontSize)
+		for _, classifier := range item.Classifiers() {
+			r.pdf.Write(r.lineHeight, " : "+classifier)
+		}
+		r.pdf.SetFont("Arial", "", r.fontSize)
+		r.pdf.Ln(r.lineHeight)
+
+		r.pdf.SetLeftMargin(left + r.indent)
+		r.pdf.SetX(left + r.indent)
+		for _, definition := range item.Definition() {
+			if err := r.renderNode(definition); err != nil {
+				r.pdf.SetLeftMargin(left)
+				return err
+			}
+		}
+		r.pdf.SetLeftMargin(left)
+	}
+	r.pdf.SetX(left)
+	return nil
+}
+
+// renderFields writes each field name in bold followed by the first paragraph
+// of its body, with the rest of the body indented below it.
+func (r *PDFRenderer) renderFields(fields []nodes.Node) error {
+	left, _, _, _ := r.pdf.GetMargins()
+	for _, child := range fields {
+		field, ok := child.(*nodes.FieldNode)
+		if !ok {
+			continue
+		}
+		r.pdf.SetX(left)
+		r.pdf.SetFont("Arial", "B", r.fontSize)
+		r.pdf.Write(r.lineHeight, field.Name()+": ")
+		r.pdf.SetFont("Arial", "", r.fontSize)
+		children := field.Children()
+		if paragraph, ok := firstParagraph(children); ok {
+			r.writeInline(paragraph)
+			children = children[1:]
+		}
+		r.pdf.Ln(r.lineHeight)
+
+		r.pdf.SetLeftMargin(left + r.indent)
+		r.pdf.SetX(left + r.indent)
+		for _, body := range children {
+			if err := r.renderNode(body); err != nil {
+				r.pdf.SetLeftMargin(left)
+				return err
+			}
+		}
+		r.pdf.SetLeftMargin(left)
+	}
+	r.pdf.SetX(left)
+	r.pdf.Ln(r.lineHeight)
+	return nil
+}
 
+// renderOptionList renders an option list in two columns, with the option
+// groups in a fixed-width font on the left and their descriptions on the
+// right. Each row starts below the taller cell of the row before it.
+func (r *PDFRenderer) renderOptionList(node *nodes.OptionListNode) error {
+	left, _, right, _ := r.pdf.GetMargins()
+	pageWidth, _ := r.pdf.GetPageSize()
+	column := (pageWidth - left - right) / 3
+	defer r.pdf.SetLeftMargin(left)
+	for _, child := range node.Children() {
+		item, ok := child.(*nodes.OptionListItemNode)
+		if !ok {
+			continue
+		}
+		top := r.pdf.GetY()
+		r.pdf.SetX(left)
+		r.pdf.SetFont("Courier", "", r.fontSize)
+		r.pdf.MultiCell(column, r.lineHeight, item.OptionGroup(), "", "", false)
+		r.pdf.SetFont("Arial", "", r.fontSize)
+		bottom := r.pdf.GetY()
+
+		r.pdf.SetLeftMargin(left + column)
+		r.pdf.SetXY(left+column, top)
+		children := item.Description()
+		if paragraph, ok := firstParagraph(children); ok {
+			r.writeInline(paragraph)
+			r.pdf.Ln(r.lineHeight)
+			children = children[1:]
+		}
+		for _, description := range children {
+			if err := r.renderNode(description); err != nil {
+				return err
+			}
+		}
+		r.pdf.SetLeftMargin(left)
+		r.pdf.SetXY(left, max(bottom, r.pdf.GetY()))
+	}
 	r.pdf.Ln(r.lineHeight)
 	return nil
 }
 
+// renderBlockQuote renders the body of a block quote indented from the
+// surrounding text, followed by its attribution.
+func (r *PDFRenderer) renderBlockQuote(node *nodes.BlockQuoteNode) error {
+	left, _, _, _ := r.pdf.GetMargins()
+	r.pdf.SetLeftMargin(left + r.indent)
+	r.pdf.SetX(left + r.indent)
+	defer r.pdf.SetLeftMargin(left)
+
+	if node.Content() != "" {
+		r.pdf.MultiCell(0, r.lineHeight, node.Content(), "", "", false)
+	}
+	if err := r.renderChildren(node); err != nil {
+		return err
+	}
+	if attribution := node.Attribution(); attribution != "" {
+		r.pdf.SetFont("Arial", "I", r.fontSize)
+		r.pdf.MultiCell(0, r.lineHeight, "-- "+attribution, "", "R", false)
+		r.pdf.SetFont("Arial", "", r.fontSize)
+		r.pdf.Ln(r.lineHeight)
+	}
+	return nil
+}
+
 func (r *PDFRenderer) renderCode(node *nodes.CodeNode) error {
 	// Set monospace font for code
 	r.pdf.SetFont("Courier", "", r.fontSize)
@@ -165,60 +450,261 @@ func (r *PDFRenderer) renderCode(node *nodes.CodeNode) error {
 	return nil
 }
 
+// renderDoctest draws a doctest block like a code block, with the commands
+// in bold so they stand apart from the expected output.
+func (r *PDFRenderer) renderDoctest(node *nodes.DoctestNode) error {
+	var lines int
+	for _, example := range node.Examples() {
+		lines += len(example.Lines())
+	}
+	startY := r.pdf.GetY()
+	r.pdf.SetFillColor(240, 240, 240)
+	r.pdf.Rect(r.marginLeft-2, startY-2, 170, float64(lines)*r.lineHeight+4, "F")
+
+	for _, example := range node.Examples() {
+		commands := 1 + len(example.Continuation)
+		for i, line := range example.Lines() {
+			if i < commands {
+				r.pdf.SetFont("Courier", "B", r.fontSize)
+			} else {
+				r.pdf.SetFont("Courier", "", r.fontSize)
+			}
+			r.pdf.MultiCell(0, r.lineHeight, line, "", "", false)
+		}
+	}
+
+	r.pdf.SetFont("Arial", "", r.fontSize)
+	r.pdf.Ln(r.lineHeight)
+	return nil
+}
+
+// renderTable draws a table with cell borders, the header rows shaded and
+// the stub columns in bold.
+// Column widths follow the relative widths of the source columns when known.
+// Each row is as high as its tallest cell, and cells spanning several rows
+// or columns are drawn across all of them.
 func (r *PDFRenderer) renderTable(node *nodes.TableNode) error {
-	headers := node.Headers()
-	rows := node.Rows()
+	const padding = 1.5
+	columns := node.Columns()
+	if columns == 0 {
+		return nil
+	}
+	grid := tableGrid(node)
+	headerRows := len(node.HeaderRows())
+	left, _, right, bottom := r.pdf.GetMargins()
+	pageWidth, pageHeight := r.pdf.GetPageSize()
+	widths := tableColumnWidths(node, pageWidth-left-right)
+	spanWidth := func(col, span int) float64 {
+		width := 0.0
+		for _, w := range widths[col:min(col+span, columns)] {
+			width += w
+		}
+		return width
+	}
 
-	// Calculate column widths
-	colWidth := 170.0 / float64(len(headers))
+	// Wrap the text of each cell to its width, and size the rows to fit.
+	lines := make(map[*nodes.TableCellNode][]string)
+	heights := make([]float64, len(grid))
+	spanning := make([][2]int, 0)
+	for i, row := range grid {
+		heights[i] = r.lineHeight + padding
+		for j, cell := range row {
+			if !isCellOrigin(grid, i, j) {
+				continue
+			}
+			r.setTableFont(i < headerRows || j < node.StubColumns())
+			for _, text := range tableCellText(cell) {
+				for _, line := range r.pdf.SplitLines([]byte(text), spanWidth(j, cell.ColSpan())-2*padding) {
+					lines[cell] = append(lines[cell], string(line))
+				}
+			}
+			if cell.RowSpan() > 1 {
+				spanning = append(spanning, [2]int{i, j})
+				continue
+			}
+			heights[i] = max(heights[i], float64(len(lines[cell]))*r.lineHeight+padding)
+		}
+	}
+	for _, origin := range spanning {
+		i, cell := origin[0], grid[origin[0]][origin[1]]
+		last := min(i+cell.RowSpan(), len(grid)) - 1
+		height := 0.0
+		for _, h := range heights[i : last+1] {
+			height += h
+		}
+		heights[last] += max(float64(len(lines[cell]))*r.lineHeight+padding-height, 0)
+	}
 
-	// Draw headers
-	r.pdf.SetFont("Arial", "B", r.fontSize)
+	if title := node.Title(); title != "" {
+		r.pdf.SetFont("Arial", "B", r.fontSize)
+		r.pdf.SetX(left)
+		r.pdf.MultiCell(0, r.lineHeight, title, "", "C", false)
+	}
+	y := r.pdf.GetY()
 	r.pdf.SetFillColor(240, 240, 240)
-
-	for _, header := range headers {
-		r.pdf.CellFormat(colWidth, r.lineHeight, header, "1", 0, "", true, 0, "")
+	for i, row := range grid {
+		if y+heights[i] > pageHeight-bottom {
+			r.pdf.AddPage()
+			y = r.pdf.GetY()
+		}
+		for j, cell := range row {
+			if !isCellOrigin(grid, i, j) {
+				continue
+			}
+			r.setTableFont(i < headerRows || j < node.StubColumns())
+			x, width, height := left+spanWidth(0, j), spanWidth(j, cell.ColSpan()), 0.0
+			for _, h := range heights[i:min(i+cell.RowSpan(), len(grid))] {
+				height += h
+			}
+			style := "D"
+			if i < headerRows {
+				style = "FD"
+			}
+			r.pdf.Rect(x, y, width, height, style)
+			for k, line := range lines[cell] {
+				r.pdf.SetXY(x+padding, y+padding/2+float64(k)*r.lineHeight)
+				r.pdf.CellFormat(width-2*padding, r.lineHeight, line, "", 0, "L", false, 0, "")
+			}
+		}
+		y += heights[i]
 	}
-	r.pdf.Ln(-1)
 
-	// Draw rows
-	r.pdf.SetFont("Arial", "", r.fontSize)
+	r.setTableFont(false)
 	r.pdf.SetFillColor(255, 255, 255)
+	r.pdf.SetXY(left, y)
+	r.pdf.Ln(r.lineHeight)
+	return nil
+}
+
+// setTableFont selects the font of header or body cells.
+func (r *PDFRenderer) setTableFont(header bool) {
+	if header {
+		r.pdf.SetFont("Arial", "B", r.fontSize)
+	} else {
+		r.pdf.SetFont("Arial", "", r.fontSize)
+	}
+}
 
-	for _, row := range rows {
-		for _, cell := range row {
-			r.pdf.CellFormat(colWidth, r.lineHeight, cell, "1", 0, "", false, 0, "")
+// tableColumnWidths divides width among the columns of table in proportion
+// to their source widths, or evenly if those are not known.
+func tableColumnWidths(table *nodes.TableNode, width float64) []float64 {
+	columns := table.Columns()
+	source := table.ColumnWidths()
+	total := 0
+	for _, w := range source {
+		total += w
+	}
+	widths := make([]float64, columns)
+	for i := range widths {
+		if len(source) == columns && total > 0 {
+			widths[i] = width * float64(source[i]) / float64(total)
+		} else {
+			widths[i] = width / float64(columns)
+		}
+	}
+	return widths
+}
+
+// tableCellText returns the text of a table cell as paragraphs, with each
+// list item as a paragraph of its own.
+func tableCellText(cell *nodes.TableCellNode) []string {
+	if len(cell.Children()) == 0 {
+		return []string{cell.Content()}
+	}
+	flatten := func(node nodes.Node) string {
+		return strings.Join(strings.Fields(nodes.TextContent(node)), " ")
+	}
+	texts := make([]string, 0, len(cell.Children()))
+	for _, child := range cell.Children() {
+		if list, ok := child.(*nodes.ListNode); ok {
+			for i, item := range list.Children() {
+				texts = append(texts, list.Marker(i)+" "+flatten(item))
+			}
+			continue
 		}
-		r.pdf.Ln(-1)
+		texts = append(texts, flatten(child))
 	}
+	return texts
+}
+
+// renderAdmonition writes the title of an admonition in bold capitals
+// above its body.
+func (r *PDFRenderer) renderAdmonition(node *nodes.AdmonitionNode) error {
+	r.pdf.SetFont("Arial", "B", r.fontSize)
+	r.pdf.Cell(0, r.lineHeight, strings.ToUpper(node.Title()))
+	r.pdf.Ln(r.lineHeight)
+	r.pdf.SetFont("Arial", "", r.fontSize)
+	return r.renderChildren(node)
+}
 
+// renderImage places an image at the current position.
+func (r *PDFRenderer) renderImage(node *nodes.ImageNode) error {
+	r.pdf.Image(node.URI(), r.pdf.GetX(), r.pdf.GetY(), 0, 0, false, "", 0, "")
 	r.pdf.Ln(r.lineHeight)
 	return nil
 }
 
-func (r *PDFRenderer) renderDirective(node *nodes.DirectiveNode) error {
-	// Handle special directives
-	switch node.Name() {
-	case "image":
-		// Handle image directive
-		if len(node.Arguments()) > 0 {
-			imagePath := node.Arguments()[0]
-			r.pdf.Image(imagePath, r.pdf.GetX(), r.pdf.GetY(), 0, 0, false, "", 0, "")
-			r.pdf.Ln(r.lineHeight)
+// renderFootnotes writes the footnotes met since the last call below a short
+// rule, in a smaller font. A label links back to the first reference to its
+// footnote.
+func (r *PDFRenderer) renderFootnotes() error {
+	if len(r.footnotes) == 0 {
+		return nil
+	}
+	footnotes := r.footnotes
+	r.footnotes = nil
+	defer func(fontSize float64) {
+		r.fontSize = fontSize
+		r.pdf.SetFont("Arial", "", fontSize)
+	}(r.fontSize)
+	r.fontSize -= 2
+
+	y := r.pdf.GetY()
+	r.pdf.Line(r.marginLeft, y, r.marginLeft+50, y)
+	r.pdf.Ln(r.lineHeight / 2)
+	r.pdf.SetFont("Arial", "", r.fontSize)
+	for _, footnote := range footnotes {
+		r.pdf.SetLink(r.link(footnote.ID()), r.pdf.GetY(), -1)
+		label := "[" + footnote.Label() + "] "
+		if backrefs := footnote.Backrefs(); len(backrefs) > 0 {
+			r.pdf.WriteLinkID(r.lineHeight, label, r.link(backrefs[0]))
+		} else {
+			r.pdf.Write(r.lineHeight, label)
+		}
+		if err := r.renderChildren(footnote); err != nil {
+			return err
 		}
-	case "note", "warning", "important":
-		// Handle admonitions
-		r.pdf.SetFillColor(245, 245, 245)
-		startY := r.pdf.GetY()
-		r.pdf.MultiCell(0, r.lineHeight, node.RawContent(), "", "", true)
-		r.pdf.SetY(startY + r.lineHeight)
 	}
+	return nil
+}
 
-	return r.renderChildren(node)
+// link returns the PDF link targeting the element with the given ID,
+// creating it on first use. The target position is set when the element is
+// written.
+func (r *PDFRenderer) link(id string) int {
+	link, ok := r.links[id]
+	if !ok {
+		link = r.pdf.AddLink()
+		r.links[id] = link
+	}
+	return link
+}
+
+// anchor makes the current position the destination of links to node's ID,
+// if it has one.
+func (r *PDFRenderer) anchor(node nodes.Node) {
+	if node.ID() != "" {
+		r.pdf.SetLink(r.link(node.ID()), r.pdf.GetY(), -1)
+	}
 }
 
 func (r *PDFRenderer) renderChildren(node nodes.Node) error {
-	return r.Render(node.Children())
+	for _, child := range node.Children() {
+		if err := r.renderNode(child); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 /* Broken but doesn't matter right now, HTML matters.